@@ -0,0 +1,34 @@
+package alias
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+
+	deleteAliasCmd "github.com/msetsma/RepoRover/cmd/alias/delete"
+	listAliasCmd "github.com/msetsma/RepoRover/cmd/alias/list"
+	setAliasCmd "github.com/msetsma/RepoRover/cmd/alias/set"
+)
+
+func NewCmdAlias(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias <command>",
+		Short: "Create command shortcuts",
+		Long: heredoc.Doc(`
+			Aliases expand to rr commands, or, when prefixed with !, to shell commands.
+			Placeholders $1, $2, ... are replaced by the arguments given to the alias within
+			the word they appear in, quoted or not; remaining arguments are appended.
+		`),
+		Example: heredoc.Doc(`
+			$ rr alias set up "group pull --all"
+			$ rr alias set st 'group status $1'
+			$ rr alias set fetch-all '!rr group exec -- "git fetch --all"'
+		`),
+	}
+
+	cmd.AddCommand(setAliasCmd.CmdSetAlias(tool))
+	cmd.AddCommand(listAliasCmd.CmdListAliases(tool))
+	cmd.AddCommand(deleteAliasCmd.CmdDeleteAlias(tool))
+
+	return cmd
+}
@@ -0,0 +1,34 @@
+package delete
+
+import (
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdDeleteAlias(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <alias>",
+		Short: "Delete an alias",
+		Args:  util.ExactArgs(1, "requires an alias name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			expansion, ok := cfg.Aliases[args[0]]
+			if !ok {
+				return fmt.Errorf("no such alias %s", args[0])
+			}
+			if err := config.Update([]string{"aliases", args[0]}, nil); err != nil {
+				return err
+			}
+			fmt.Fprintf(tool.IOStreams.Out, "Deleted alias %s; was %s\n", args[0], expansion)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+package expand
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+var placeholderRE = regexp.MustCompile(`\$\d+`)
+
+// ExpandAlias expands args[1] when it names an alias. args is the full
+// argument list including the program name. The returned arguments exclude
+// the program name. For shell aliases, prefixed with !, the returned
+// arguments are a shell invocation to run instead of a rr command.
+func ExpandAlias(aliases map[string]string, args []string) (expanded []string, isShell bool, err error) {
+	if len(args) < 2 {
+		return args[1:], false, nil
+	}
+
+	expansion, ok := aliases[args[1]]
+	if !ok {
+		return args[1:], false, nil
+	}
+	extra := args[2:]
+
+	if strings.HasPrefix(expansion, "!") {
+		shell, err := findShell()
+		if err != nil {
+			return nil, false, err
+		}
+		expanded = []string{shell, "-c", expansion[1:]}
+		if len(extra) > 0 {
+			// The shell receives the remaining arguments as $1, $2, ...
+			expanded = append(expanded, "--")
+			expanded = append(expanded, extra...)
+		}
+		return expanded, true, nil
+	}
+
+	// Placeholders are replaced after splitting, so each argument stays
+	// within its word with spaces, quotes and placeholders kept as they are.
+	words, err := shlex.Split(expansion)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid alias %q: %w", args[1], err)
+	}
+	used := make([]bool, len(extra))
+	missing := false
+	for _, word := range words {
+		expanded = append(expanded, placeholderRE.ReplaceAllStringFunc(word, func(placeholder string) string {
+			i, _ := strconv.Atoi(placeholder[1:])
+			if i < 1 || i > len(extra) {
+				missing = true
+				return placeholder
+			}
+			used[i-1] = true
+			return extra[i-1]
+		}))
+	}
+	if missing {
+		return nil, false, fmt.Errorf("not enough arguments for alias %q: %s", args[1], expansion)
+	}
+	for i, arg := range extra {
+		if !used[i] {
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, false, nil
+}
+
+func findShell() (string, error) {
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		return "", fmt.Errorf("unable to find sh to run shell alias: %w", err)
+	}
+	return shell, nil
+}
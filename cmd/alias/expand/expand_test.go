@@ -0,0 +1,144 @@
+package expand
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"up":     "group pull --all",
+		"st":     "group status $1",
+		"swap":   "group exec $2 -- git checkout $1",
+		"twice":  "group exec $1 -- echo $1",
+		"quoted": `group exec backend -- git commit -m "wip $1"`,
+		"high":   "group status $2",
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "not an alias",
+			args: []string{"rr", "group", "list"},
+			want: []string{"group", "list"},
+		},
+		{
+			name: "no arguments",
+			args: []string{"rr"},
+			want: []string{},
+		},
+		{
+			name: "extra arguments are appended",
+			args: []string{"rr", "up", "--ff-only", "backend"},
+			want: []string{"group", "pull", "--all", "--ff-only", "backend"},
+		},
+		{
+			name: "placeholder",
+			args: []string{"rr", "st", "backend"},
+			want: []string{"group", "status", "backend"},
+		},
+		{
+			name: "placeholders out of order with extra arguments",
+			args: []string{"rr", "swap", "main", "backend", "--serial"},
+			want: []string{"group", "exec", "backend", "--", "git", "checkout", "main", "--serial"},
+		},
+		{
+			name: "placeholder used twice",
+			args: []string{"rr", "twice", "backend"},
+			want: []string{"group", "exec", "backend", "--", "echo", "backend"},
+		},
+		{
+			name: "spaces stay in one argument",
+			args: []string{"rr", "st", "my group"},
+			want: []string{"group", "status", "my group"},
+		},
+		{
+			name: "quotes stay as they are",
+			args: []string{"rr", "st", `it's "here"`},
+			want: []string{"group", "status", `it's "here"`},
+		},
+		{
+			name: "placeholders in arguments are not expanded",
+			args: []string{"rr", "swap", "$2", "backend"},
+			want: []string{"group", "exec", "backend", "--", "git", "checkout", "$2"},
+		},
+		{
+			name: "placeholder within quotes",
+			args: []string{"rr", "quoted", "half done"},
+			want: []string{"group", "exec", "backend", "--", "git", "commit", "-m", "wip half done"},
+		},
+		{
+			name:    "missing argument",
+			args:    []string{"rr", "st"},
+			wantErr: true,
+		},
+		{
+			name:    "skipped placeholder",
+			args:    []string{"rr", "high", "backend"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isShell, err := ExpandAlias(aliases, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expanded to %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isShell {
+				t.Error("expanded to a shell alias")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expanded to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandShellAlias(t *testing.T) {
+	shell, err := findShell()
+	if err != nil {
+		t.Skip(err)
+	}
+	aliases := map[string]string{"each": `!for r in "$@"; do echo "$r"; done`}
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "without arguments",
+			args: []string{"rr", "each"},
+			want: []string{shell, "-c", `for r in "$@"; do echo "$r"; done`},
+		},
+		{
+			// Arguments are passed to the shell as they are, not
+			// substituted into the script.
+			name: "with arguments",
+			args: []string{"rr", "each", "a b", "$1", "-x"},
+			want: []string{shell, "-c", `for r in "$@"; do echo "$r"; done`, "--", "a b", "$1", "-x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isShell, err := ExpandAlias(aliases, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isShell {
+				t.Error("not expanded to a shell alias")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expanded to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
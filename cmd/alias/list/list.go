@@ -0,0 +1,43 @@
+package list
+
+import (
+	"sort"
+
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdListAliases(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List aliases",
+		Aliases: []string{"ls"},
+		Args:    util.NoArgsQuoteReminder,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			if len(cfg.Aliases) == 0 {
+				return util.NewNoResultsError("no aliases configured")
+			}
+
+			names := make([]string, 0, len(cfg.Aliases))
+			for name := range cfg.Aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			for _, name := range names {
+				tp.AddField(name+":", cs.Bold)
+				tp.AddField(cfg.Aliases[name], nil)
+				tp.EndRow()
+			}
+			return tp.Render()
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+package set
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdSetAlias(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <alias> <expansion>",
+		Short: "Create or replace an alias",
+		Args:  util.ExactArgs(2, "requires an alias name and its expansion"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, expansion := args[0], args[1]
+			if name == "" || strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") {
+				return fmt.Errorf("invalid alias name %q", name)
+			}
+			if isCommand(cmd.Root(), []string{name}) {
+				return fmt.Errorf("%q is a built-in command and cannot be aliased", name)
+			}
+			if !strings.HasPrefix(expansion, "!") {
+				words, err := shlex.Split(expansion)
+				if err != nil {
+					return fmt.Errorf("invalid expansion: %w", err)
+				}
+				if !isCommand(cmd.Root(), words) {
+					return fmt.Errorf("expansion %q does not start with a rr command; prefix it with ! for a shell alias", expansion)
+				}
+			}
+
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			_, replaced := cfg.Aliases[name]
+			if err := config.Update([]string{"aliases", name}, expansion); err != nil {
+				return err
+			}
+
+			if replaced {
+				fmt.Fprintf(tool.IOStreams.Out, "Changed alias %s to: %s\n", name, expansion)
+			} else {
+				fmt.Fprintf(tool.IOStreams.Out, "Added alias %s: %s\n", name, expansion)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// isCommand reports whether args start with a built-in command.
+func isCommand(root *cobra.Command, args []string) bool {
+	c, _, err := root.Find(args)
+	return err == nil && c != root
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 
+	getConfigValueCmd "github.com/msetsma/RepoRover/cmd/config/get"
 	setConfigValueCmd "github.com/msetsma/RepoRover/cmd/config/set"
 	showConfigCmd "github.com/msetsma/RepoRover/cmd/config/show"
+	unsetConfigValueCmd "github.com/msetsma/RepoRover/cmd/config/unset"
 )
 
 func NewCmdConfig(tool *util.CmdTool) *cobra.Command {
@@ -16,12 +18,15 @@ func NewCmdConfig(tool *util.CmdTool) *cobra.Command {
 		Long:  `Make changes to the configuration of RepoRover`,
 		Example: heredoc.Doc(`
 			$ rr config show
-			$ rr config set -n <group name>
+			$ rr config set concurrency 16
+			$ rr config get default_branch
 		`),
 	}
 
 	cmd.AddCommand(showConfigCmd.CmdShowConfig(tool))
 	cmd.AddCommand(setConfigValueCmd.CmdSetConfig(tool))
+	cmd.AddCommand(getConfigValueCmd.CmdGetConfig(tool))
+	cmd.AddCommand(unsetConfigValueCmd.CmdUnsetConfig(tool))
 
 	return cmd
 }
@@ -0,0 +1,42 @@
+package get
+
+import (
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func CmdGetConfig(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get <key>",
+		Short:   "Print a value from the config file",
+		Example: "$ rr config get concurrency",
+		Args:    util.ExactArgs(1, "requires a key"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			switch v := value.(type) {
+			case string, int, bool:
+				fmt.Fprintln(tool.IOStreams.Out, v)
+			default:
+				out, err := yaml.Marshal(v)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(tool.IOStreams.Out, string(out))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -1,18 +1,54 @@
 package set
 
 import (
-	//"github.com/msetsma/RepoRover/core/config"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/config"
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 )
 
-func CmdSetConfig(cfg *util.CmdTool) *cobra.Command {
+func CmdSetConfig(tool *util.CmdTool) *cobra.Command {
 
 	cmd := &cobra.Command{
-		Use:   "set",
+		Use:   "set <key> <value>",
 		Short: "Set values in config file",
+		Long: heredoc.Docf(`
+			Set a value in the config file. Keys are dotted paths into rover.yaml and values
+			are checked against the type of the key. Aliases are set with 'rr alias set',
+			which checks that they don't shadow a command.
+
+			Known keys:
+			  %s
+		`, strings.Join(config.Keys(), "\n  ")),
+		Example: heredoc.Doc(`
+			$ rr config set concurrency 16
+			$ rr config set integrations.azure.url https://dev.azure.com
+			$ rr config set credentials.timeout 30
+		`),
+		Args: util.ExactArgs(2, "requires a key and a value"),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return nil
+			path, err := config.KeyPath(args[0])
+			if err != nil {
+				return err
+			}
+			if path[0] == "aliases" {
+				return fmt.Errorf("set aliases with 'rr alias set <name> <expansion>'")
+			}
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+			return config.Update(path, value)
 		},
 	}
 
@@ -3,21 +3,28 @@ package show
 import (
 	"fmt"
 
+	"github.com/msetsma/RepoRover/core/config"
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 func CmdShowConfig(tool *util.CmdTool) *cobra.Command {
+	var group string
+
 	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Display the config values.",
+		Long:  "Display the config values. With --group the effective settings of the group are shown along with where each value comes from.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configData, err := tool.Config()
 			if err != nil {
 				fmt.Fprintln(tool.IOStreams.ErrOut, "Error loading configuration:", err)
 				return err
 			}
+			if group != "" {
+				return showGroup(tool, configData, group)
+			}
 			yamlData, err := yaml.Marshal(configData)
 			if err != nil {
 				fmt.Fprintln(tool.IOStreams.ErrOut, "Failed to marshal configuration to YAML:", err)
@@ -28,5 +35,34 @@ func CmdShowConfig(tool *util.CmdTool) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&group, "group", "g", "", "Show the effective settings of a group")
+
 	return cmd
 }
+
+func showGroup(tool *util.CmdTool, cfg *config.Manifest, group string) error {
+	db, err := tool.Database()
+	if err != nil {
+		return err
+	}
+	if exists, err := db.GroupExists(group); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("group '%s' does not exist", group)
+	}
+
+	cs := tool.IOStreams.ColorScheme()
+	tp := util.NewTablePrinter(tool.IOStreams.Out)
+	tp.AddHeader("key", "value", "origin")
+	for _, s := range cfg.Resolve(group).List() {
+		tp.AddField(s.Key, cs.Bold)
+		tp.AddField(fmt.Sprint(s.Value), nil)
+		if s.Origin == config.OriginGroup {
+			tp.AddField(string(s.Origin), cs.Green)
+		} else {
+			tp.AddField(string(s.Origin), cs.Gray)
+		}
+		tp.EndRow()
+	}
+	return tp.Render()
+}
@@ -0,0 +1,37 @@
+package unset
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdUnsetConfig(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Restore a config value to its default",
+		Long:  "Restore a config value to its default, or remove an entry from a map such as aliases.",
+		Example: heredoc.Doc(`
+			$ rr config unset concurrency
+			$ rr config unset aliases.up
+		`),
+		Args: util.ExactArgs(1, "requires a key"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Unset(args[0]); err != nil {
+				return err
+			}
+			path, err := config.KeyPath(args[0])
+			if err != nil {
+				return err
+			}
+			return config.Update(path, nil)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+
+	migrateDBCmd "github.com/msetsma/RepoRover/cmd/db/migrate"
+)
+
+func NewCmdDB(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db <command>",
+		Short: "Manage the RepoRover database",
+		Example: heredoc.Doc(`
+			$ rr db migrate --status
+		`),
+	}
+
+	cmd.AddCommand(migrateDBCmd.CmdMigrateDB(tool))
+
+	return cmd
+}
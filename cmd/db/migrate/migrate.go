@@ -0,0 +1,89 @@
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdMigrateDB(tool *util.CmdTool) *cobra.Command {
+	var status bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the database to the current schema",
+		Long: heredoc.Doc(`
+			Apply pending schema migrations to the database. Every command migrates the
+			database when it opens it, so this is only needed to migrate ahead of time.
+
+			With --status the migrations are listed with when they were applied, without
+			changing the database.
+		`),
+		Example: heredoc.Doc(`
+			$ rr db migrate
+			$ rr db migrate --status
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := storage.DatabasePath(storage.DefaultDatabaseName)
+			before, err := storage.ReadSchemaStatus(path)
+			if err != nil {
+				return err
+			}
+			if status {
+				return printStatus(tool, path, before)
+			}
+
+			if _, err := tool.Database(); err != nil {
+				return err
+			}
+			pending := before.Pending()
+			if len(pending) == 0 {
+				fmt.Fprintf(tool.IOStreams.Out, "Database is up to date at version %d\n", before.Version)
+				return nil
+			}
+			for _, m := range pending {
+				fmt.Fprintf(tool.IOStreams.Out, "Applied migration %d: %s\n", m.Version, m.Description)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&status, "status", false, "List applied and pending migrations without migrating")
+
+	return cmd
+}
+
+func printStatus(tool *util.CmdTool, path string, s *storage.SchemaStatus) error {
+	out := tool.IOStreams.Out
+	cs := tool.IOStreams.ColorScheme()
+
+	fmt.Fprintf(out, "Database: %s\n", path)
+	fmt.Fprintf(out, "Schema version: %d (this rr supports up to %d)\n", s.Version, storage.LatestSchemaVersion)
+	if s.Version > storage.LatestSchemaVersion {
+		fmt.Fprintln(out, cs.Red("The database was migrated by a newer rr; upgrade rr to use it."))
+	}
+	fmt.Fprintln(out)
+
+	tp := util.NewTablePrinter(out)
+	tp.AddHeader("version", "description", "applied")
+	for _, m := range s.Migrations {
+		tp.AddField(strconv.Itoa(m.Version), cs.Bold)
+		tp.AddField(m.Description, nil)
+		switch {
+		case m.AppliedAt.IsZero():
+			tp.AddField("pending", cs.Yellow)
+		case m.Version > storage.LatestSchemaVersion:
+			tp.AddField(m.AppliedAt.Local().Format(time.DateTime)+" (unknown to this rr)", cs.Red)
+		default:
+			tp.AddField(m.AppliedAt.Local().Format(time.DateTime), cs.Green)
+		}
+		tp.EndRow()
+	}
+	return tp.Render()
+}
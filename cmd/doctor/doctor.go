@@ -0,0 +1,125 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/doctor"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+
+	pathsDoctorCmd "github.com/msetsma/RepoRover/cmd/doctor/paths"
+
+	_ "github.com/msetsma/RepoRover/core/integrations/azure"
+	_ "github.com/msetsma/RepoRover/core/integrations/github"
+	_ "github.com/msetsma/RepoRover/core/integrations/gitlab"
+)
+
+// exitFailed is the exit code when a check fails, distinct from the codes of
+// other errors.
+const exitFailed = 16
+
+func NewCmdDoctor(tool *util.CmdTool) *cobra.Command {
+	var validate bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [<command>]",
+		Short: "Diagnose the RepoRover setup",
+		Long: heredoc.Docf(`
+			Check the environment rr runs in: the git binary, the config file, the database
+			and its schema, the clone destinations, the repositories of every group and the
+			tokens of the integrations. Every check reports pass, warn or fail, with what to
+			do about warnings and failures.
+
+			With --validate the integration tokens are sent to the configured URL of their
+			integration to check they are accepted.
+
+			Exits with status %d when a check fails; warnings don't change the exit status.
+		`, exitFailed),
+		Example: heredoc.Doc(`
+			$ rr doctor
+			$ rr doctor --validate
+			$ rr doctor paths
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			var results []doctor.Result
+			skip := func(check, reason string) {
+				results = append(results, doctor.Result{Check: check, Status: doctor.Warn, Message: "skipped: " + reason})
+			}
+
+			results = append(results, doctor.Git(ctx))
+
+			cfg, result := doctor.Config()
+			results = append(results, result)
+
+			openable, result := doctor.Database(storage.DatabasePath(storage.DefaultDatabaseName))
+			results = append(results, result)
+
+			if cfg == nil {
+				skip("clone destination", "the config could not be loaded")
+				skip("groups", "the config could not be loaded")
+				skip("integrations", "the config could not be loaded")
+				return report(tool, results)
+			}
+			results = append(results, doctor.CloneDestinations(cfg)...)
+
+			if openable {
+				db, err := tool.Database()
+				if err != nil {
+					return err
+				}
+				groups, err := doctor.Groups(ctx, db, cfg)
+				if err != nil {
+					return err
+				}
+				results = append(results, groups...)
+			} else if result.Status != doctor.Pass {
+				skip("groups", "the database is not usable as is")
+			}
+
+			results = append(results, doctor.Integrations(ctx, cfg, validate)...)
+			return report(tool, results)
+		},
+	}
+
+	cmd.Flags().BoolVar(&validate, "validate", false, "Check integration tokens against their integration's URL")
+
+	cmd.AddCommand(pathsDoctorCmd.CmdDoctorPaths(tool))
+
+	return cmd
+}
+
+func report(tool *util.CmdTool, results []doctor.Result) error {
+	out := tool.IOStreams.Out
+	cs := tool.IOStreams.ColorScheme()
+
+	counts := map[doctor.Status]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		label := fmt.Sprintf("%-4s", r.Status)
+		switch r.Status {
+		case doctor.Pass:
+			label = cs.Green(label)
+		case doctor.Warn:
+			label = cs.Yellow(label)
+		default:
+			label = cs.Red(label)
+		}
+		fmt.Fprintf(out, "%s  %s: %s\n", label, cs.Bold(r.Check), r.Message)
+		for _, detail := range r.Details {
+			fmt.Fprintf(out, "        %s\n", cs.Gray(detail))
+		}
+		if r.Remedy != "" {
+			fmt.Fprintf(out, "        %s\n", r.Remedy)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n", counts[doctor.Pass], counts[doctor.Warn], counts[doctor.Fail])
+	if counts[doctor.Fail] > 0 {
+		return &util.ExitError{Code: exitFailed}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/paths"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+// sourceConfig marks a location set in the configuration file.
+const sourceConfig = "config"
+
+func CmdDoctorPaths(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "paths",
+		Short: "Show where RepoRover keeps its files",
+		Long: heredoc.Docf(`
+			Show every location RepoRover uses, why it was chosen and whether it exists.
+
+			Locations follow the XDG base directories: configuration and templates in
+			$XDG_CONFIG_HOME/rover, the database and cloned groups in $XDG_DATA_HOME/rover.
+			Setting %[1]s places all of them below that directory. Files left by older
+			versions of rr are used from their old location, marked legacy, until they are
+			moved.
+		`, "`"+paths.HomeEnv+"`"),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := paths.Resolve()
+			clone := p.Groups
+			// Only read the config when it exists; loading it creates it.
+			if _, err := os.Stat(p.ConfigFile.Path); err == nil {
+				cfg, err := tool.Config()
+				if err != nil {
+					return err
+				}
+				if cfg.Paths.Groups != clone.Path {
+					clone = paths.Location{Path: cfg.Paths.Groups, Source: sourceConfig}
+				}
+			}
+
+			rows := []struct {
+				name     string
+				location paths.Location
+			}{
+				{"config", p.Config},
+				{"config file", p.ConfigFile},
+				{"templates", p.Templates},
+				{"data", p.Data},
+				{"database", p.DatabaseFile(storage.DefaultDatabaseName)},
+				{"clone destination", clone},
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("name", "path", "source", "exists")
+			var legacy []paths.Location
+			for _, row := range rows {
+				tp.AddField(row.name, cs.Bold)
+				tp.AddField(row.location.Path, nil)
+				if row.location.Source == paths.SourceLegacy {
+					legacy = append(legacy, row.location)
+					tp.AddField(row.location.Source, cs.Yellow)
+				} else {
+					tp.AddField(row.location.Source, cs.Gray)
+				}
+				if _, err := os.Stat(row.location.Path); err == nil {
+					tp.AddField("yes", cs.Green)
+				} else {
+					tp.AddField("no", cs.Gray)
+				}
+				tp.EndRow()
+			}
+			if err := tp.Render(); err != nil {
+				return err
+			}
+
+			if len(legacy) > 0 {
+				fmt.Fprintln(tool.IOStreams.ErrOut)
+				for _, l := range legacy {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "%s %s was created by an older rr; move it to %s\n", cs.Yellow("!"), l.Path, l.Preferred)
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -1,6 +1,8 @@
 package activate
 
 import (
+	"fmt"
+
 	"github.com/msetsma/RepoRover/core/config"
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
@@ -9,10 +11,33 @@ import (
 func CmdSetActiveGroup(tool *util.CmdTool) *cobra.Command {
 
 	cmd := &cobra.Command{
-		Use:   "active",
+		Use:   "active [<group name>]",
 		Short: "Set the active group",
+		Long:  "Set the group used by commands when none is given. Without arguments the active group is printed.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return nil
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				fmt.Fprintln(tool.IOStreams.Out, cfg.ActiveGroup)
+				return nil
+			}
+
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			exists, err := db.GroupExists(args[0])
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("group '%s' does not exist; create it with `rr group init %s`", args[0], args[0])
+			}
+
+			return config.Update([]string{"active_group"}, args[0])
 		},
 	}
 
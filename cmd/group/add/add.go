@@ -0,0 +1,59 @@
+package add
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupAdd(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <group name> <url or path>...",
+		Short: "Add repositories to a group",
+		Long: heredoc.Doc(`
+			Add repositories to a group by clone URL or by path to an existing working tree.
+
+			Local working trees are identified by their origin remote. URLs that are not
+			cloned yet are recorded as pending clone.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group add backend https://github.com/user/repo1.git ~/projects/repo2
+		`),
+		Args: util.MinimumArgs(2, "requires a group name and at least one repository"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+
+			group, failed := args[0], false
+			for _, arg := range args[1:] {
+				repo, err := workspace.Resolve(cmd.Context(), arg)
+				if err == nil {
+					err = db.AddRepository(group, repo)
+				}
+				if err != nil {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "failed to add %s: %v\n", arg, err)
+					failed = true
+					continue
+				}
+
+				if repo.PendingClone() {
+					fmt.Fprintf(tool.IOStreams.Out, "Added %s to %s (pending clone)\n", repo.Name, group)
+				} else {
+					fmt.Fprintf(tool.IOStreams.Out, "Added %s to %s (%s)\n", repo.Name, group, repo.Path)
+				}
+			}
+
+			if failed {
+				return util.ErrSilent
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,22 @@
+package analyze
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	commitsAnalyzeCmd "github.com/msetsma/RepoRover/cmd/group/analyze/commits"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupAnalyze(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze <subject>",
+		Short: "Analyze the history of the repositories in a group",
+		Example: heredoc.Doc(`
+			$ rr group analyze commits backend --since 2023-01-01
+		`),
+	}
+
+	cmd.AddCommand(commitsAnalyzeCmd.CmdAnalyzeCommits(tool))
+
+	return cmd
+}
@@ -0,0 +1,314 @@
+package commits
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+type analyzeOptions struct {
+	Since  string
+	Until  string
+	Author string
+	Limit  int
+	Format string
+}
+
+// report is the result of an analysis, also the shape of the JSON output.
+type report struct {
+	Group        string                    `json:"group"`
+	Since        *time.Time                `json:"since,omitempty"`
+	Until        *time.Time                `json:"until,omitempty"`
+	Author       string                    `json:"author,omitempty"`
+	Commits      int                       `json:"commits"`
+	Insertions   int                       `json:"insertions"`
+	Deletions    int                       `json:"deletions"`
+	Repositories []models.ActiveRepository `json:"repositories"`
+	Authors      []models.AuthorActivity   `json:"authors"`
+	Weeks        []models.WeeklyActivity   `json:"weeks"`
+	Files        []models.FileActivity     `json:"files"`
+}
+
+func CmdAnalyzeCommits(tool *util.CmdTool) *cobra.Command {
+	opts := &analyzeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "commits [<group name>]",
+		Short: "Report commit activity of a group",
+		Long: heredoc.Doc(`
+			Report the commits per repository and for the whole group, the top authors, the
+			commits per week and the busiest files. Defaults to the active group.
+
+			The report covers the commits recorded by 'rr group ingest'. Dates are given as
+			YYYY-MM-DD or in RFC 3339 format; --until includes the given day. --author
+			matches part of an author's name or email. Weeks start on Monday in UTC; weeks
+			of the window without commits are listed with none.
+
+			With --format csv every row has the columns section, name, commits, insertions
+			and deletions, where section is one of repository, total, author, week or file.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group analyze commits backend --since 2023-01-01
+			$ rr group analyze commits backend --since 2024-01-01 --until 2024-03-31 --author alice
+			$ rr group analyze commits backend --format json
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := storage.CommitFilter{Author: opts.Author}
+			var err error
+			if filter.Since, err = parseDate(opts.Since, false); err != nil {
+				return util.FlagErrorf("invalid --since: %v", err)
+			}
+			if filter.Until, err = parseDate(opts.Until, true); err != nil {
+				return util.FlagErrorf("invalid --until: %v", err)
+			}
+			if opts.Limit < 1 {
+				return util.FlagErrorf("--limit must be at least 1")
+			}
+			switch opts.Format {
+			case "table", "json", "csv":
+			default:
+				return util.FlagErrorf("invalid --format %q: use table, json or csv", opts.Format)
+			}
+
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			if filter.Group, err = util.GroupArg(args, cfg); err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+
+			r, err := analyze(db, filter, opts.Limit)
+			if err != nil {
+				return err
+			}
+			switch opts.Format {
+			case "json":
+				enc := json.NewEncoder(tool.IOStreams.Out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(r)
+			case "csv":
+				return writeCSV(tool, r)
+			}
+			if r.Commits == 0 {
+				return util.NewNoResultsError(fmt.Sprintf("no commits recorded for %s in this window; run 'rr group ingest %s' first", r.Group, r.Group))
+			}
+			return writeTables(tool, r)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only count commits on or after this date")
+	cmd.Flags().StringVar(&opts.Until, "until", "", "Only count commits on or before this date")
+	cmd.Flags().StringVar(&opts.Author, "author", "", "Only count commits by a matching author")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", 10, "Number of authors and files to list")
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Output format: table, json or csv")
+
+	return cmd
+}
+
+// parseDate parses a flag value. A day given as the end of a window is
+// included by moving to the start of the next day.
+func parseDate(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date like 2023-01-31", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func analyze(db *storage.Database, filter storage.CommitFilter, limit int) (*report, error) {
+	r := &report{Group: filter.Group, Author: filter.Author}
+	if !filter.Since.IsZero() {
+		r.Since = &filter.Since
+	}
+	if !filter.Until.IsZero() {
+		r.Until = &filter.Until
+	}
+
+	var err error
+	if r.Repositories, err = db.MostActiveRepositories(filter); err != nil {
+		return nil, err
+	}
+	for _, repo := range r.Repositories {
+		r.Commits += repo.ActivityCount
+		r.Insertions += repo.Insertions
+		r.Deletions += repo.Deletions
+	}
+	if r.Authors, err = db.TopAuthors(filter, limit); err != nil {
+		return nil, err
+	}
+	if r.Weeks, err = db.CommitsPerWeek(filter); err != nil {
+		return nil, err
+	}
+	if r.Files, err = db.BusiestFiles(filter, limit); err != nil {
+		return nil, err
+	}
+	r.Weeks = fillWeeks(r.Weeks, filter.Since, filter.Until, time.Now())
+
+	// An empty window is reported as empty lists rather than null.
+	if r.Repositories == nil {
+		r.Repositories = []models.ActiveRepository{}
+	}
+	if r.Authors == nil {
+		r.Authors = []models.AuthorActivity{}
+	}
+	if r.Files == nil {
+		r.Files = []models.FileActivity{}
+	}
+	return r, nil
+}
+
+// fillWeeks adds the weeks without commits to weeks, from the week of since,
+// or the first week with commits, through the week of until, or of now. Weeks
+// start on Monday in UTC, like those of CommitsPerWeek.
+func fillWeeks(weeks []models.WeeklyActivity, since, until, now time.Time) []models.WeeklyActivity {
+	filled := []models.WeeklyActivity{}
+	var start time.Time
+	switch {
+	case !since.IsZero():
+		start = weekStart(since)
+	case len(weeks) > 0:
+		start = weeks[0].Week
+	default:
+		return filled
+	}
+	// until is excluded from the window.
+	end := weekStart(now)
+	if !until.IsZero() {
+		end = weekStart(until.Add(-time.Nanosecond))
+	}
+
+	commits := make(map[time.Time]int, len(weeks))
+	for _, w := range weeks {
+		commits[w.Week] = w.Commits
+		if w.Week.After(end) {
+			end = w.Week
+		}
+	}
+	for week := start; !week.After(end); week = week.AddDate(0, 0, 7) {
+		filled = append(filled, models.WeeklyActivity{Week: week, Commits: commits[week]})
+	}
+	return filled
+}
+
+// weekStart returns midnight UTC of the Monday of the week of t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func writeTables(tool *util.CmdTool, r *report) error {
+	out := tool.IOStreams.Out
+	cs := tool.IOStreams.ColorScheme()
+
+	tp := util.NewTablePrinter(out)
+	tp.AddHeader("repo", "commits", "insertions", "deletions", "last commit")
+	for _, repo := range r.Repositories {
+		tp.AddField(repo.Name, cs.Bold)
+		tp.AddField(strconv.Itoa(repo.ActivityCount), nil)
+		tp.AddField("+"+strconv.Itoa(repo.Insertions), cs.Green)
+		tp.AddField("-"+strconv.Itoa(repo.Deletions), cs.Red)
+		tp.AddField(repo.LastCommit.Local().Format(time.DateOnly), cs.Gray)
+		tp.EndRow()
+	}
+	tp.AddField("total", cs.Cyan)
+	tp.AddField(strconv.Itoa(r.Commits), cs.Cyan)
+	tp.AddField("+"+strconv.Itoa(r.Insertions), cs.Green)
+	tp.AddField("-"+strconv.Itoa(r.Deletions), cs.Red)
+	tp.AddField("", nil)
+	tp.EndRow()
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	tp = util.NewTablePrinter(out)
+	tp.AddHeader("author", "email", "commits", "insertions", "deletions")
+	for _, a := range r.Authors {
+		tp.AddField(a.Name, cs.Bold)
+		tp.AddField(a.Email, cs.Gray)
+		tp.AddField(strconv.Itoa(a.Commits), nil)
+		tp.AddField("+"+strconv.Itoa(a.Insertions), cs.Green)
+		tp.AddField("-"+strconv.Itoa(a.Deletions), cs.Red)
+		tp.EndRow()
+	}
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	tp = util.NewTablePrinter(out)
+	tp.AddHeader("week", "commits")
+	for _, w := range r.Weeks {
+		tp.AddField(w.Week.Format(time.DateOnly), cs.Bold)
+		tp.AddField(strconv.Itoa(w.Commits), nil)
+		tp.EndRow()
+	}
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	tp = util.NewTablePrinter(out)
+	tp.AddHeader("repo", "file", "commits", "insertions", "deletions")
+	for _, f := range r.Files {
+		tp.AddField(f.Repository, cs.Bold)
+		tp.AddField(f.Path, nil)
+		tp.AddField(strconv.Itoa(f.Commits), nil)
+		tp.AddField("+"+strconv.Itoa(f.Insertions), cs.Green)
+		tp.AddField("-"+strconv.Itoa(f.Deletions), cs.Red)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+func writeCSV(tool *util.CmdTool, r *report) error {
+	w := csv.NewWriter(tool.IOStreams.Out)
+	row := func(section, name string, commits int, lines ...int) {
+		record := []string{section, name, strconv.Itoa(commits), "", ""}
+		for i, n := range lines {
+			record[3+i] = strconv.Itoa(n)
+		}
+		_ = w.Write(record)
+	}
+
+	_ = w.Write([]string{"section", "name", "commits", "insertions", "deletions"})
+	for _, repo := range r.Repositories {
+		row("repository", repo.Name, repo.ActivityCount, repo.Insertions, repo.Deletions)
+	}
+	row("total", r.Group, r.Commits, r.Insertions, r.Deletions)
+	for _, a := range r.Authors {
+		row("author", fmt.Sprintf("%s <%s>", a.Name, a.Email), a.Commits, a.Insertions, a.Deletions)
+	}
+	for _, week := range r.Weeks {
+		row("week", week.Week.Format(time.DateOnly), week.Commits)
+	}
+	for _, f := range r.Files {
+		row("file", f.Repository+":"+f.Path, f.Commits, f.Insertions, f.Deletions)
+	}
+	w.Flush()
+	return w.Error()
+}
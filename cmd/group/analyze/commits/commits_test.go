@@ -0,0 +1,75 @@
+package commits
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+func TestFillWeeks(t *testing.T) {
+	// 2024-03-04 is a Monday.
+	week := func(day int) time.Time { return time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC) }
+	now := time.Date(2024, 3, 20, 15, 0, 0, 0, time.UTC)
+	stored := []models.WeeklyActivity{{Week: week(4), Commits: 3}, {Week: week(18), Commits: 1}}
+
+	tests := []struct {
+		name         string
+		weeks        []models.WeeklyActivity
+		since, until time.Time
+		want         []models.WeeklyActivity
+	}{
+		{
+			name:  "gap",
+			weeks: stored,
+			want:  []models.WeeklyActivity{{Week: week(4), Commits: 3}, {Week: week(11)}, {Week: week(18), Commits: 1}},
+		},
+		{
+			name:  "window",
+			weeks: stored[:1],
+			since: time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC),
+			// until is excluded, so the week starting on it is not listed.
+			until: week(18),
+			want:  []models.WeeklyActivity{{Week: time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)}, {Week: week(4), Commits: 3}, {Week: week(11)}},
+		},
+		{
+			name:  "up to now",
+			weeks: stored[:1],
+			want:  []models.WeeklyActivity{{Week: week(4), Commits: 3}, {Week: week(11)}, {Week: week(18)}},
+		},
+		{
+			name:  "empty window",
+			since: week(5),
+			until: week(12),
+			want:  []models.WeeklyActivity{{Week: week(4)}, {Week: week(11)}},
+		},
+		{
+			name: "nothing ingested",
+			want: []models.WeeklyActivity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fillWeeks(tt.weeks, tt.since, tt.until, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("weeks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	for _, day := range []time.Time{
+		monday,
+		time.Date(2024, 3, 6, 23, 59, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 23, 59, 0, 0, time.UTC),
+		// Monday 01:00 in UTC+2 is still Sunday in UTC.
+		time.Date(2024, 3, 11, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+	} {
+		if got := weekStart(day); !got.Equal(monday) {
+			t.Errorf("weekStart(%s) = %s, want %s", day, got, monday)
+		}
+	}
+}
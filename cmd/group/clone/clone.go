@@ -0,0 +1,82 @@
+package clone
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupClone(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone [<group name>]",
+		Short: "Clone the missing repositories of a group",
+		Long: heredoc.Doc(`
+			Clone every repository of a group that is not on disk yet into
+			<clone_destination>/<group>/<repo>. Repositories that are already present are
+			skipped. Defaults to the active group.
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			settings, err := util.GroupSettings(cmd, cfg, group)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			ws := workspace.New(settings.CloneDestination, group)
+			ws.Credentials = git.Credentials(settings.Credentials)
+			var results []workspace.CloneResult
+			_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Cloning %s", group), func() error {
+				results = ws.Clone(cmd.Context(), repos, settings.Concurrency)
+				return nil
+			})
+
+			out, errOut := tool.IOStreams.Out, tool.IOStreams.ErrOut
+			failed := 0
+			for _, r := range results {
+				switch r.Status {
+				case workspace.CloneStatusFailed:
+					failed++
+					fmt.Fprintf(errOut, "failed   %s: %v\n", r.Repo.Name, r.Err)
+					continue
+				case workspace.CloneStatusCloned:
+					fmt.Fprintf(out, "cloned   %s -> %s\n", r.Repo.Name, r.Dir)
+				case workspace.CloneStatusPresent:
+					fmt.Fprintf(out, "present  %s\n", r.Repo.Name)
+				}
+				if r.Repo.PendingClone() {
+					if err := db.SetRepositoryPath(group, r.Repo.Name, r.Dir); err != nil {
+						fmt.Fprintf(errOut, "failed to record path of %s: %v\n", r.Repo.Name, err)
+					}
+				}
+			}
+
+			fmt.Fprintf(out, "%d of %d repositories ready in %s\n", len(results)-failed, len(results), group)
+			if failed > 0 {
+				return util.ErrSilent
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
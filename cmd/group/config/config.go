@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	roverConfig "github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdGroupConfig(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config <command>",
+		Short: "Manage group-specific configuration",
+		Long: heredoc.Docf(`
+			Override global settings for a single group. Group values take precedence over
+			global ones; flags given to a command take precedence over both.
+
+			Keys:
+			  %s
+		`, strings.Join(roverConfig.GroupKeys(), "\n  ")),
+		Example: heredoc.Doc(`
+			$ rr group config set legacy default-branch master
+			$ rr group config unset legacy default-branch
+			$ rr config show --group legacy
+		`),
+	}
+
+	cmd.AddCommand(cmdSet(tool))
+	cmd.AddCommand(cmdGet(tool))
+	cmd.AddCommand(cmdUnset(tool))
+
+	return cmd
+}
+
+func cmdSet(tool *util.CmdTool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <group name> <key> <value>",
+		Short: "Override a setting for a group",
+		Args:  util.ExactArgs(3, "requires a group name, a key and a value"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return update(tool, args[0], args[1], func(o *roverConfig.GroupOverrides) error {
+				return o.Set(args[1], args[2])
+			})
+		},
+	}
+}
+
+func cmdUnset(tool *util.CmdTool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <group name> <key>",
+		Short: "Remove a group override so the global value applies",
+		Args:  util.ExactArgs(2, "requires a group name and a key"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return update(tool, args[0], args[1], func(o *roverConfig.GroupOverrides) error {
+				return o.Unset(args[1])
+			})
+		},
+	}
+}
+
+func cmdGet(tool *util.CmdTool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <group name> <key>",
+		Short: "Print the effective value of a setting for a group",
+		Args:  util.ExactArgs(2, "requires a group name and a key"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			// Validate the key even though the effective value is printed.
+			o := cfg.Groups[args[0]]
+			if _, err := o.Get(args[1]); err != nil {
+				return err
+			}
+			key := strings.ReplaceAll(args[1], "-", "_")
+			for _, s := range cfg.Resolve(args[0]).List() {
+				if s.Key == key {
+					fmt.Fprintln(tool.IOStreams.Out, s.Value)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// update applies fn to the overrides of group and saves key to the config
+// file. Keys that are no longer overridden are removed, and groups without any
+// override left are dropped from the file.
+func update(tool *util.CmdTool, group, key string, fn func(*roverConfig.GroupOverrides) error) error {
+	db, err := tool.Database()
+	if err != nil {
+		return err
+	}
+	if exists, err := db.GroupExists(group); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("group '%s' does not exist", group)
+	}
+
+	cfg, err := tool.Config()
+	if err != nil {
+		return err
+	}
+	o := cfg.Groups[group]
+	if err := fn(&o); err != nil {
+		return err
+	}
+
+	path, err := roverConfig.GroupKeyPath(group, key)
+	if err != nil {
+		return err
+	}
+	value, err := o.Get(key)
+	if err != nil {
+		return err
+	}
+	return roverConfig.Update(path, value)
+}
@@ -1,13 +1,121 @@
 package delete
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
 	"github.com/spf13/cobra"
 )
 
-var deleteGroupCmd = &cobra.Command{
-	Use:   "delete",
-	Short: "Delete an existing group",
-	RunE: func(cmd *cobra.Command, args []string) error {
+func CmdGroupDelete(tool *util.CmdTool) *cobra.Command {
+	var purge, yes bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <group name>",
+		Short: "Delete an existing group",
+		Long: heredoc.Doc(`
+			Delete a group. Repositories on disk are kept unless --purge is given, in which
+			case working trees cloned under the clone destination are removed as well.
+			Working trees that were added from elsewhere are never removed.
+		`),
+		Args: util.ExactArgs(1, "requires a group name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group := args[0]
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			ws := workspace.New(cfg.Resolve(group).CloneDestination, group)
+			var purged []string
+			if purge {
+				purged = clonedTrees(ws, repos)
+			}
+
+			if !yes {
+				if !tool.IOStreams.CanPrompt() {
+					return util.FlagErrorf("--yes required when not running interactively")
+				}
+				question := fmt.Sprintf("Delete group %s with %d repositories?", group, len(repos))
+				if len(purged) > 0 {
+					question = fmt.Sprintf("Delete group %s and remove %d working trees under %s?", group, len(purged), ws.Dir())
+				}
+				ok, err := util.Confirm(tool.IOStreams, question)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return util.ErrCancel
+				}
+			}
+
+			if err := db.DeleteGroup(group); err != nil {
+				return err
+			}
+			for _, dir := range purged {
+				if err := os.RemoveAll(dir); err != nil {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "failed to remove %s: %v\n", dir, err)
+				}
+			}
+			if purge {
+				// Only succeeds when nothing but our clones lived there.
+				_ = os.Remove(ws.Dir())
+			}
+
+			if cfg.ActiveGroup == group {
+				if err := config.Update([]string{"active_group"}, ""); err != nil {
+					return err
+				}
+			}
+			if _, ok := cfg.Groups[group]; ok {
+				if err := config.Update([]string{"groups", group}, nil); err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintf(tool.IOStreams.Out, "Deleted group %s\n", group)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&purge, "purge", false, "Also remove working trees cloned under the clone destination")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+
+	return cmd
+}
+
+// clonedTrees returns the existing working trees of repos that live inside the
+// group directory under the clone destination.
+func clonedTrees(ws workspace.Workspace, repos []models.GroupRepository) []string {
+	root, err := filepath.Abs(ws.Dir())
+	if err != nil {
 		return nil
-	},
+	}
+
+	var dirs []string
+	for _, repo := range repos {
+		dir, err := filepath.Abs(ws.RepoDir(repo))
+		if err != nil || !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
@@ -0,0 +1,192 @@
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+// exitFailed is the exit code when the command fails in some repositories,
+// or fails differently across them. When it fails the same way everywhere it
+// ran, its own exit code is passed through, even where that collides with
+// rr's own codes.
+const exitFailed = 32
+
+type execOptions struct {
+	failFast   bool
+	serial     bool
+	onlyDirty  bool
+	onlyBranch string
+}
+
+func CmdGroupExec(tool *util.CmdTool) *cobra.Command {
+	opts := &execOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "exec [<group name>] -- <command>...",
+		Short: "Run a command in every repository of a group",
+		Long: heredoc.Docf(`
+			Run a command in the working tree of every cloned repository of a group. Output
+			is printed per repository, each line prefixed with the repository name.
+
+			A single quoted command is run through the shell; multiple arguments are
+			executed directly. The exit code of each failure is listed. When the command
+			fails with the same exit code in every repository it ran in, rr exits with
+			that code; when it fails in only some, or with different codes, with status %d.
+		`, exitFailed),
+		Example: heredoc.Doc(`
+			$ rr group exec backend -- "git fetch --all"
+			$ rr group exec --only-dirty -- git status --short
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return util.FlagErrorf("a command is required after --")
+			}
+			if dash > 1 {
+				return util.FlagErrorf("too many arguments before --")
+			}
+			command := args[dash:]
+
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args[:dash], cfg)
+			if err != nil {
+				return err
+			}
+			settings, err := util.GroupSettings(cmd, cfg, group)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			ws := workspace.New(settings.CloneDestination, group)
+			repos, skipped := filter(cmd.Context(), ws, repos, opts, settings.Concurrency)
+
+			limit := settings.Concurrency
+			if opts.serial {
+				limit = 1
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			results := ws.Exec(cmd.Context(), repos, command, workspace.ExecOptions{
+				Limit:    limit,
+				FailFast: opts.failFast,
+				OnDone: func(r workspace.ExecResult) {
+					if r.Skipped {
+						return
+					}
+					prefix := cs.Cyan(fmt.Sprintf("[%s]", r.Repo.Name)) + " "
+					writePrefixed(tool.IOStreams.Out, prefix, r.Stdout)
+					writePrefixed(tool.IOStreams.ErrOut, prefix, r.Stderr)
+					if r.Err != nil {
+						fmt.Fprintf(tool.IOStreams.ErrOut, "%s%v\n", prefix, r.Err)
+					}
+				},
+			})
+
+			return summarize(tool, results, skipped)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop at the first repository where the command fails")
+	cmd.Flags().BoolVar(&opts.serial, "serial", false, "Run in one repository at a time")
+	cmd.Flags().BoolVar(&opts.onlyDirty, "only-dirty", false, "Only run in repositories with local changes")
+	cmd.Flags().StringVar(&opts.onlyBranch, "only-branch", "", "Only run in repositories with `branch` checked out")
+
+	return cmd
+}
+
+// filter drops repositories excluded by the --only-* flags. Repositories
+// whose status cannot be read are returned as skipped.
+func filter(ctx context.Context, ws workspace.Workspace, repos []models.GroupRepository, opts *execOptions, limit int) ([]models.GroupRepository, []string) {
+	if !opts.onlyDirty && opts.onlyBranch == "" {
+		return repos, nil
+	}
+
+	var filtered []models.GroupRepository
+	var skipped []string
+	for _, r := range ws.Status(ctx, repos, limit) {
+		if r.Status == nil {
+			skipped = append(skipped, r.Repo.Name)
+			continue
+		}
+		if opts.onlyDirty && !r.Status.Dirty() {
+			continue
+		}
+		if opts.onlyBranch != "" && r.Status.Branch != opts.onlyBranch {
+			continue
+		}
+		filtered = append(filtered, r.Repo)
+	}
+	return filtered, skipped
+}
+
+func writePrefixed(w io.Writer, prefix string, output []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fmt.Fprintf(w, "%s%s\n", prefix, scanner.Text())
+	}
+}
+
+func summarize(tool *util.CmdTool, results []workspace.ExecResult, skipped []string) error {
+	var failed []string
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			skipped = append(skipped, r.Repo.Name)
+		case r.Failed():
+			failed = append(failed, fmt.Sprintf("%s (exit %d)", r.Repo.Name, r.ExitCode))
+		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(tool.IOStreams.ErrOut, "skipped %d repositories: %v\n", len(skipped), skipped)
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	fmt.Fprintf(tool.IOStreams.ErrOut, "command failed in %d of %d repositories: %v\n", len(failed), len(results), failed)
+	return &util.ExitError{Code: exitCode(results)}
+}
+
+// exitCode returns the exit code the command failed with in every repository
+// it ran in, or exitFailed when it succeeded in some, failed with different
+// codes or could not be started.
+func exitCode(results []workspace.ExecResult) int {
+	code := 0
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			continue
+		case !r.Failed(), r.Err != nil:
+			return exitFailed
+		case code == 0:
+			code = r.ExitCode
+		case code != r.ExitCode:
+			return exitFailed
+		}
+	}
+	if code == 0 {
+		return exitFailed
+	}
+	return code
+}
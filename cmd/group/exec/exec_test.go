@@ -0,0 +1,84 @@
+package exec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+)
+
+func TestWritePrefixed(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"lines", "one\ntwo\n", "[api] one\n[api] two\n"},
+		{"no trailing newline", "one\ntwo", "[api] one\n[api] two\n"},
+		{"blank line", "one\n\ntwo\n", "[api] one\n[api] \n[api] two\n"},
+		{"nothing", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writePrefixed(&buf, "[api] ", []byte(tt.output))
+			if buf.String() != tt.want {
+				t.Errorf("wrote %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	ok := workspace.ExecResult{}
+	skipped := workspace.ExecResult{Skipped: true, ExitCode: 1, Err: errors.New("killed")}
+	exit := func(code int) workspace.ExecResult { return workspace.ExecResult{ExitCode: code} }
+	notStarted := workspace.ExecResult{ExitCode: 1, Err: errors.New("executable file not found")}
+
+	tests := []struct {
+		name    string
+		results []workspace.ExecResult
+		want    int
+	}{
+		{"same everywhere", []workspace.ExecResult{exit(2), exit(2)}, 2},
+		{"fail fast", []workspace.ExecResult{exit(7), skipped, skipped}, 7},
+		{"some succeeded", []workspace.ExecResult{exit(2), ok}, exitFailed},
+		{"different codes", []workspace.ExecResult{exit(2), exit(3)}, exitFailed},
+		{"not started", []workspace.ExecResult{notStarted, notStarted}, exitFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.results); got != tt.want {
+				t.Errorf("exitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var out bytes.Buffer
+	tool := &util.CmdTool{IOStreams: &util.IOStreams{Out: &out, ErrOut: &out}}
+	results := []workspace.ExecResult{
+		{Repo: models.GroupRepository{Name: "api"}, ExitCode: 2},
+		{Repo: models.GroupRepository{Name: "web"}},
+		{Repo: models.GroupRepository{Name: "docs"}, Skipped: true},
+	}
+
+	err := summarize(tool, results, []string{"worker"})
+	var exitErr *util.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != exitFailed {
+		t.Errorf("err = %v, want exit %d", err, exitFailed)
+	}
+	want := "skipped 2 repositories: [worker docs]\ncommand failed in 1 of 3 repositories: [api (exit 2)]\n"
+	if out.String() != want {
+		t.Errorf("printed %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := summarize(tool, results[1:2], nil); err != nil || out.Len() != 0 {
+		t.Errorf("success: err = %v, printed %q", err, out.String())
+	}
+}
@@ -1,8 +1,24 @@
 package group
 
 import (
-	initGroupCmd "github.com/msetsma/RepoRover/cmd/group/init"
 	"github.com/MakeNowJust/heredoc"
+	activeGroupCmd "github.com/msetsma/RepoRover/cmd/group/activate"
+	addGroupCmd "github.com/msetsma/RepoRover/cmd/group/add"
+	analyzeGroupCmd "github.com/msetsma/RepoRover/cmd/group/analyze"
+	cloneGroupCmd "github.com/msetsma/RepoRover/cmd/group/clone"
+	configGroupCmd "github.com/msetsma/RepoRover/cmd/group/config"
+	deleteGroupCmd "github.com/msetsma/RepoRover/cmd/group/delete"
+	execGroupCmd "github.com/msetsma/RepoRover/cmd/group/exec"
+	importGroupCmd "github.com/msetsma/RepoRover/cmd/group/import"
+	ingestGroupCmd "github.com/msetsma/RepoRover/cmd/group/ingest"
+	initGroupCmd "github.com/msetsma/RepoRover/cmd/group/init"
+	listGroupCmd "github.com/msetsma/RepoRover/cmd/group/list"
+	pullGroupCmd "github.com/msetsma/RepoRover/cmd/group/pull"
+	removeGroupCmd "github.com/msetsma/RepoRover/cmd/group/remove"
+	reportGroupCmd "github.com/msetsma/RepoRover/cmd/group/report"
+	statsGroupCmd "github.com/msetsma/RepoRover/cmd/group/stats"
+	statusGroupCmd "github.com/msetsma/RepoRover/cmd/group/status"
+	syncGroupCmd "github.com/msetsma/RepoRover/cmd/group/sync"
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +30,31 @@ func NewCmdGroup(tool *util.CmdTool) *cobra.Command {
 		Long:  `Make changes, get information on groups.`,
 		Example: heredoc.Doc(`
 			$ rr group list
-			$ rr group delete -n <group name>
+			$ rr group delete <group name>
+			$ rr group add <group name> <url or path>...
 		`),
 		GroupID: "group",
 	}
 
+	cmd.PersistentFlags().IntP("concurrency", "j", 0, "Number of repositories to work on at once (default: from config)")
+
 	cmd.AddCommand(initGroupCmd.CmdGroupInit(tool))
+	cmd.AddCommand(listGroupCmd.CmdGroupList(tool))
+	cmd.AddCommand(activeGroupCmd.CmdSetActiveGroup(tool))
+	cmd.AddCommand(deleteGroupCmd.CmdGroupDelete(tool))
+	cmd.AddCommand(addGroupCmd.CmdGroupAdd(tool))
+	cmd.AddCommand(removeGroupCmd.CmdGroupRemove(tool))
+	cmd.AddCommand(cloneGroupCmd.CmdGroupClone(tool))
+	cmd.AddCommand(pullGroupCmd.CmdGroupPull(tool))
+	cmd.AddCommand(statusGroupCmd.CmdGroupStatus(tool))
+	cmd.AddCommand(execGroupCmd.CmdGroupExec(tool))
+	cmd.AddCommand(syncGroupCmd.CmdGroupSync(tool))
+	cmd.AddCommand(configGroupCmd.NewCmdGroupConfig(tool))
+	cmd.AddCommand(importGroupCmd.CmdGroupImport(tool))
+	cmd.AddCommand(statsGroupCmd.CmdGroupStats(tool))
+	cmd.AddCommand(ingestGroupCmd.CmdGroupIngest(tool))
+	cmd.AddCommand(analyzeGroupCmd.CmdGroupAnalyze(tool))
+	cmd.AddCommand(reportGroupCmd.CmdGroupReport(tool))
 
 	return cmd
 }
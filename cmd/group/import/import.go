@@ -0,0 +1,238 @@
+package importcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+
+	// Register the providers.
+	_ "github.com/msetsma/RepoRover/core/integrations/azure"
+	_ "github.com/msetsma/RepoRover/core/integrations/github"
+	_ "github.com/msetsma/RepoRover/core/integrations/gitlab"
+)
+
+type importOptions struct {
+	Scope           integrations.Scope
+	Match           string
+	Include         []string
+	Exclude         []string
+	ExcludeForks    bool
+	ExcludeArchived bool
+	ExcludeDisabled bool
+	DryRun          bool
+}
+
+func CmdGroupImport(tool *util.CmdTool) *cobra.Command {
+	opts := &importOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import <provider> <group name>",
+		Short: "Import repositories into a group from a hosting provider",
+		Long: heredoc.Docf(`
+			Add the repositories discovered on a hosting provider to a group. Repositories
+			are added by their remote URL and recorded as pending clone. Repositories
+			already in the group are left as they are.
+
+			Providers: %[1]s
+
+			azure   Lists the repositories of --project, or of every project of --org
+			        with --all-projects. The personal access token is read from
+			        integrations.azure.api_token.
+			github  Lists the repositories of --org or --user, or those tagged with
+			        --topic. The token is read from integrations.github.api_token; set
+			        integrations.github.url to the API of a GitHub Enterprise Server.
+			gitlab  Lists the projects of the group --org and all of its subgroups. The
+			        repositories are laid out on disk like the subgroups. The token is
+			        read from integrations.gitlab.api_token; set integrations.gitlab.url
+			        for a self-managed instance.
+
+			Use --match to keep repositories whose name matches a regular expression,
+			--include to keep only the named repositories and --exclude to skip some.
+			When repositories of different projects share a name they are added as
+			<project>/<name>, which the filters match as well. Disabled repositories
+			cannot be cloned and are skipped unless --exclude-disabled=false is given.
+		`, strings.Join(integrations.Providers(), ", ")),
+		Example: heredoc.Doc(`
+			$ rr group import azure backend --org acme --project platform --match '^svc-'
+			$ rr group import azure everything --org acme --all-projects --exclude-forks
+			$ rr group import github tools --org acme --topic cli --exclude-archived
+			$ rr group import gitlab acquired --org acme/legacy
+		`),
+		Args: util.ExactArgs(2, "requires a provider and a group name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd.Context(), tool, opts, args[0], args[1])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Scope.Org, "org", "", "Organization to import from")
+	cmd.Flags().StringVar(&opts.Scope.User, "user", "", "User whose repositories to import")
+	cmd.Flags().StringVar(&opts.Scope.Project, "project", "", "Project to import from")
+	cmd.Flags().BoolVar(&opts.Scope.AllProjects, "all-projects", false, "Import from every project of the organization")
+	cmd.Flags().StringVar(&opts.Scope.Topic, "topic", "", "Only import repositories tagged with this topic")
+	cmd.Flags().StringVar(&opts.Match, "match", "", "Only import repositories whose name matches this regular expression")
+	cmd.Flags().StringSliceVar(&opts.Include, "include", nil, "Only import the repositories with these names")
+	cmd.Flags().StringSliceVar(&opts.Exclude, "exclude", nil, "Skip the repositories with these names")
+	cmd.Flags().BoolVar(&opts.ExcludeForks, "exclude-forks", false, "Skip repositories that are forks")
+	cmd.Flags().BoolVar(&opts.ExcludeArchived, "exclude-archived", false, "Skip archived repositories")
+	cmd.Flags().BoolVar(&opts.ExcludeDisabled, "exclude-disabled", true, "Skip disabled repositories")
+	cmd.Flags().BoolVarP(&opts.DryRun, "dry-run", "n", false, "List the repositories that would be imported without adding them")
+
+	return cmd
+}
+
+func runImport(ctx context.Context, tool *util.CmdTool, opts *importOptions, providerName, group string) error {
+	var match *regexp.Regexp
+	if opts.Match != "" {
+		var err error
+		if match, err = regexp.Compile(opts.Match); err != nil {
+			return util.FlagErrorf("invalid --match expression: %v", err)
+		}
+	}
+
+	cfg, err := tool.Config()
+	if err != nil {
+		return err
+	}
+	provider, err := integrations.New(providerName, cfg.Integrations)
+	if err != nil {
+		if errors.Is(err, integrations.ErrUnknownProvider) {
+			return util.FlagErrorWrap(err)
+		}
+		return err
+	}
+
+	db, err := tool.Database()
+	if err != nil {
+		return err
+	}
+	if exists, err := db.GroupExists(group); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("%w: %s", storage.ErrGroupNotFound, group)
+	}
+
+	remote, err := provider.Discover(ctx, opts.Scope)
+	if errors.Is(err, integrations.ErrInvalidScope) {
+		return util.FlagErrorWrap(err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	// Cache what was discovered, including repositories that are filtered out.
+	// A dry run leaves the database as it is.
+	for i := range remote {
+		remote[i].ID = workspace.RepositoryID(remote[i].RemoteURL)
+		if opts.DryRun {
+			continue
+		}
+		if err := db.SaveRepository(&remote[i]); err != nil {
+			return fmt.Errorf("failed to save repository '%s': %w", remote[i].Name, err)
+		}
+	}
+
+	names := localNames(remote)
+	var selected []int
+	disabled := 0
+	for i, repo := range remote {
+		name := names[i]
+		candidates := []string{repo.Name, name}
+		if match != nil && !match.MatchString(repo.Name) && !match.MatchString(name) {
+			continue
+		}
+		if len(opts.Include) > 0 && !containsAny(opts.Include, candidates) {
+			continue
+		}
+		if containsAny(opts.Exclude, candidates) {
+			continue
+		}
+		if (opts.ExcludeForks && repo.Fork) || (opts.ExcludeArchived && repo.Archived) {
+			continue
+		}
+		if opts.ExcludeDisabled && repo.Disabled {
+			disabled++
+			continue
+		}
+		selected = append(selected, i)
+	}
+	switch {
+	case disabled == 1:
+		fmt.Fprintln(tool.IOStreams.ErrOut, "Skipped 1 disabled repository")
+	case disabled > 1:
+		fmt.Fprintf(tool.IOStreams.ErrOut, "Skipped %d disabled repositories\n", disabled)
+	}
+	if len(selected) == 0 {
+		return util.NewNoResultsError("no repositories matched")
+	}
+
+	out, failed := tool.IOStreams.Out, false
+	for _, i := range selected {
+		repo, name := remote[i], names[i]
+		if opts.DryRun {
+			fmt.Fprintf(out, "Would add %s (%s)\n", name, repo.RemoteURL)
+			continue
+		}
+		err := db.AddRepository(group, models.GroupRepository{
+			RepositoryID:  repo.ID,
+			Name:          name,
+			RemoteURL:     repo.RemoteURL,
+			DefaultBranch: repo.DefaultBranch,
+		})
+		switch {
+		case errors.Is(err, storage.ErrRepositoryExists):
+			fmt.Fprintf(out, "Skipped %s: already in %s\n", name, group)
+		case err != nil:
+			fmt.Fprintf(tool.IOStreams.ErrOut, "failed to add %s: %v\n", name, err)
+			failed = true
+		default:
+			fmt.Fprintf(out, "Added %s to %s (pending clone)\n", name, group)
+		}
+	}
+
+	if failed {
+		return util.ErrSilent
+	}
+	return nil
+}
+
+// localNames returns the name each repository gets in the group: the group
+// path reported by the provider, the plain repository name, or
+// <project>/<name> when several projects use that name.
+func localNames(repos []models.Repository) []string {
+	count := map[string]int{}
+	for _, repo := range repos {
+		count[repo.Name]++
+	}
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		switch {
+		case repo.GroupPath != "":
+			names[i] = repo.GroupPath
+		case count[repo.Name] > 1 && repo.Project != "":
+			names[i] = repo.Project + "/" + repo.Name
+		default:
+			names[i] = repo.Name
+		}
+	}
+	return names
+}
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if slices.Contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
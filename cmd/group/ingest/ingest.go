@@ -0,0 +1,140 @@
+package ingest
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+type ingestOptions struct {
+	Branches    []string
+	AllBranches bool
+}
+
+func CmdGroupIngest(tool *util.CmdTool) *cobra.Command {
+	opts := &ingestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ingest [<group name>]",
+		Short: "Record the commit history of every repository in a group",
+		Long: heredoc.Doc(`
+			Read the commit history of every cloned repository in a group and store it for
+			analysis: author, date, subject and the lines changed per file. Defaults to the
+			active group and to the default branch of each repository.
+
+			Ingesting is incremental: only commits made since the last run are read for each
+			branch. A branch whose history was rewritten is read again in full. Run
+			'rr group pull' first to ingest the latest commits.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group ingest backend
+			$ rr group ingest backend --branch main --branch release
+			$ rr group ingest backend --all-branches
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := util.MutuallyExclusive("specify only one of --branch or --all-branches",
+				len(opts.Branches) > 0, opts.AllBranches); err != nil {
+				return err
+			}
+
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			settings, err := util.GroupSettings(cmd, cfg, group)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			since := make(map[string]map[string]string, len(repos))
+			for _, repo := range repos {
+				if since[repo.RepositoryID], err = db.IngestedBranches(repo.RepositoryID); err != nil {
+					return err
+				}
+			}
+
+			ws := workspace.New(settings.CloneDestination, group)
+			var results []workspace.CommitsResult
+			_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Reading commits of %s", group), func() error {
+				results = ws.Commits(cmd.Context(), repos, workspace.CommitsOptions{
+					Branches:      opts.Branches,
+					AllBranches:   opts.AllBranches,
+					DefaultBranch: settings.DefaultBranch,
+					Since:         since,
+					Limit:         settings.Concurrency,
+				})
+				return nil
+			})
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("repo", "branch", "new", "head")
+			failed := false
+			for _, r := range results {
+				switch {
+				case r.Err != nil:
+					fmt.Fprintf(tool.IOStreams.ErrOut, "failed to read %s: %v\n", r.Repo.Name, r.Err)
+					failed = true
+					continue
+				case r.NotCloned:
+					tp.AddField(r.Repo.Name, cs.Bold)
+					tp.AddField("not cloned", cs.Yellow)
+					tp.EndRow()
+					continue
+				}
+
+				for _, b := range r.Branches {
+					if b.Err != nil {
+						fmt.Fprintf(tool.IOStreams.ErrOut, "failed to read %s of %s: %v\n", b.Branch, r.Repo.Name, b.Err)
+						failed = true
+						continue
+					}
+					added, err := db.SaveCommits(r.Repo.RepositoryID, b.Branch, b.Head, b.Commits)
+					if err != nil {
+						return err
+					}
+					tp.AddField(r.Repo.Name, cs.Bold)
+					tp.AddField(b.Branch, cs.Cyan)
+					if added > 0 {
+						tp.AddField(strconv.Itoa(added), cs.Green)
+					} else {
+						tp.AddField("0", cs.Gray)
+					}
+					tp.AddField(git.ShortSHA(b.Head), cs.Gray)
+					tp.EndRow()
+				}
+			}
+			if err := tp.Render(); err != nil {
+				return err
+			}
+
+			if failed {
+				return util.ErrSilent
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&opts.Branches, "branch", "b", nil, "Branch to ingest; can be repeated (default: the default branch)")
+	cmd.Flags().BoolVar(&opts.AllBranches, "all-branches", false, "Ingest every local branch")
+
+	return cmd
+}
@@ -1,25 +1,120 @@
 package init
 
 import (
+	"fmt"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
 	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/templates"
 	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
 	"github.com/spf13/cobra"
 )
 
 func CmdGroupInit(tool *util.CmdTool) *cobra.Command {
+	var (
+		templateName string
+		vars         map[string]string
+	)
+
 	cmd := &cobra.Command{
-		Use:   "init",
-		Short: "Display the group values.",
-		Args:  cobra.ExactArgs(1),
+		Use:   "init <group name>",
+		Short: "Create a new group and make it active.",
+		Long: heredoc.Doc(`
+			Create a new group and make it active. With --template the group is filled with
+			the repositories and settings of a saved template; placeholders in the template
+			are filled in with --var.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group init backend
+			$ rr group init backend --template backend-team --var Org=acme
+		`),
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := tool.Config()
+			group := args[0]
+			if _, err := tool.Config(); err != nil {
+				return err
+			}
+
+			var t *templates.Template
+			var overrides config.GroupOverrides
+			if templateName != "" {
+				saved, err := templates.Load(templateName)
+				if err != nil {
+					return err
+				}
+				if t, err = saved.Render(vars); err != nil {
+					return err
+				}
+				if err := t.ApplyOverrides(&overrides); err != nil {
+					return fmt.Errorf("template '%s': %w", templateName, err)
+				}
+			} else if len(vars) > 0 {
+				return util.FlagErrorf("--var requires --template")
+			}
+
+			db, err := tool.Database()
 			if err != nil {
 				return err
 			}
-			cfg.ActiveGroup = args[0]
-			return config.Update(cfg)
+			if err := db.CreateGroup(group); err != nil {
+				return err
+			}
+
+			failed := false
+			if t != nil {
+				added := 0
+				for _, r := range t.Repositories {
+					repo, err := workspace.Resolve(cmd.Context(), r.URL)
+					if err == nil && r.Name != "" {
+						repo.Name = r.Name
+					}
+					if err == nil {
+						err = db.AddRepository(group, repo)
+					}
+					if err != nil {
+						fmt.Fprintf(tool.IOStreams.ErrOut, "failed to add %s: %v\n", r.URL, err)
+						failed = true
+						continue
+					}
+					added++
+				}
+				fmt.Fprintf(tool.IOStreams.Out, "Created group %s from template %s with %d repositories\n", group, templateName, added)
+
+				keys := make([]string, 0, len(t.Overrides))
+				for key := range t.Overrides {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					path, err := config.GroupKeyPath(group, key)
+					if err != nil {
+						return err
+					}
+					value, err := overrides.Get(key)
+					if err != nil {
+						return err
+					}
+					if err := config.Update(path, value); err != nil {
+						return err
+					}
+				}
+			}
+
+			if err := config.Update([]string{"active_group"}, group); err != nil {
+				return err
+			}
+			// The group is created and active; only report the failed adds.
+			if failed {
+				return util.ErrSilent
+			}
+			return nil
 		},
 	}
 
+	cmd.Flags().StringVarP(&templateName, "template", "t", "", "Create the group from a saved template")
+	cmd.Flags().StringToStringVar(&vars, "var", nil, "Fill the template placeholder `name` with value, as name=value")
+
 	return cmd
 }
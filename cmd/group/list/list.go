@@ -1,13 +1,57 @@
-package delete
+package list
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 )
 
-var listGroupsCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all groups",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return nil
-	},
+func CmdGroupList(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List all groups",
+		Aliases: []string{"ls"},
+		Args:    util.NoArgsQuoteReminder,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			groups, err := db.ListGroups()
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				return util.NewNoResultsError("no groups found; create one with `rr group init <group name>`")
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("", "group", "repos", "created")
+			for _, g := range groups {
+				if g.Name == cfg.ActiveGroup {
+					tp.AddField("*", cs.Green)
+					tp.AddField(g.Name, cs.Green)
+				} else {
+					tp.AddField("", nil)
+					tp.AddField(g.Name, cs.Bold)
+				}
+				tp.AddField(strconv.Itoa(g.RepositoryCount), nil)
+				tp.AddField(g.CreatedAt.Local().Format("2006-01-02"), cs.Gray)
+				tp.EndRow()
+			}
+			if err := tp.Render(); err != nil {
+				return fmt.Errorf("failed to print groups: %w", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
 }
@@ -0,0 +1,127 @@
+package pull
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupPull(tool *util.CmdTool) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "pull [<group name>]",
+		Short: "Fast-forward every repository of a group",
+		Long: heredoc.Doc(`
+			Fetch every repository of a group and fast-forward its current branch to its
+			upstream. Repositories with uncommitted changes or diverged branches are left
+			untouched and reported as failures. Defaults to the active group.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group pull backend
+			$ rr group pull --all
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := util.MutuallyExclusive("specify a group or --all, not both", all, len(args) > 0); err != nil {
+				return err
+			}
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+
+			var groups []string
+			if all {
+				list, err := db.ListGroups()
+				if err != nil {
+					return err
+				}
+				for _, g := range list {
+					groups = append(groups, g.Name)
+				}
+			} else {
+				group, err := util.GroupArg(args, cfg)
+				if err != nil {
+					return err
+				}
+				groups = []string{group}
+			}
+
+			var results []workspace.PullResult
+			for _, group := range groups {
+				settings, err := util.GroupSettings(cmd, cfg, group)
+				if err != nil {
+					return err
+				}
+				repos, err := db.GroupRepositories(group)
+				if err != nil {
+					return err
+				}
+				ws := workspace.New(settings.CloneDestination, group)
+				ws.Credentials = git.Credentials(settings.Credentials)
+				_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Pulling %s", group), func() error {
+					for _, r := range ws.Pull(cmd.Context(), repos, settings.Concurrency) {
+						if all {
+							r.Repo.Name = group + "/" + r.Repo.Name
+						}
+						results = append(results, r)
+					}
+					return nil
+				})
+			}
+
+			return printResults(tool, results)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Pull the repositories of every group")
+
+	return cmd
+}
+
+func printResults(tool *util.CmdTool, results []workspace.PullResult) error {
+	cs := tool.IOStreams.ColorScheme()
+	tp := util.NewTablePrinter(tool.IOStreams.Out)
+	tp.AddHeader("repo", "branch", "old", "new", "outcome")
+
+	var failed []workspace.PullResult
+	for _, r := range results {
+		tp.AddField(r.Repo.Name, cs.Bold)
+		tp.AddField(r.Branch, cs.Cyan)
+		tp.AddField(git.ShortSHA(r.OldSHA), cs.Gray)
+		tp.AddField(git.ShortSHA(r.NewSHA), cs.Gray)
+		switch {
+		case r.Outcome.Failed():
+			tp.AddField(string(r.Outcome), cs.Red)
+			failed = append(failed, r)
+		case r.Outcome == workspace.PullUpdated:
+			tp.AddField(string(r.Outcome), cs.Green)
+		case r.Outcome == workspace.PullNotCloned:
+			tp.AddField(string(r.Outcome), cs.Yellow)
+		default:
+			tp.AddField(string(r.Outcome), nil)
+		}
+		tp.EndRow()
+	}
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	fmt.Fprintf(tool.IOStreams.ErrOut, "\n%d of %d repositories failed to pull:\n", len(failed), len(results))
+	for _, r := range failed {
+		fmt.Fprintf(tool.IOStreams.ErrOut, "  %s: %v\n", r.Repo.Name, r.Err)
+	}
+	return util.ErrSilent
+}
@@ -0,0 +1,98 @@
+package remove
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupRemove(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <group name> <repo>...",
+		Short: "Remove repositories from a group",
+		Long: heredoc.Doc(`
+			Remove repositories from a group. Repositories can be given by name, clone URL
+			or working tree path. Files on disk are left untouched.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group remove backend repo1
+			$ rr group remove backend ~/projects/repo2
+		`),
+		Args: util.MinimumArgs(2, "requires a group name and at least one repository"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+
+			group := args[0]
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			failed := false
+			for _, arg := range args[1:] {
+				repo, ok := match(repos, arg)
+				if !ok {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "%s is not part of %s\n", arg, group)
+					failed = true
+					continue
+				}
+				if err := db.RemoveRepository(group, repo.Name); err != nil {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "failed to remove %s: %v\n", arg, err)
+					failed = true
+					continue
+				}
+				fmt.Fprintf(tool.IOStreams.Out, "Removed %s from %s\n", repo.Name, group)
+			}
+
+			if failed {
+				return util.ErrSilent
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// match finds the group repository referred to by arg, which may be a name,
+// a clone URL or a path to the working tree.
+func match(repos []models.GroupRepository, arg string) (models.GroupRepository, bool) {
+	for _, repo := range repos {
+		if repo.Name == arg {
+			return repo, true
+		}
+	}
+
+	if workspace.IsRemoteURL(arg) {
+		id := workspace.RepositoryID(arg)
+		for _, repo := range repos {
+			if repo.RepositoryID == id {
+				return repo, true
+			}
+		}
+		return models.GroupRepository{}, false
+	}
+
+	dir, err := workspace.ExpandHome(arg)
+	if err != nil {
+		return models.GroupRepository{}, false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return models.GroupRepository{}, false
+	}
+	for _, repo := range repos {
+		if repo.Path != "" && filepath.Clean(repo.Path) == dir {
+			return repo, true
+		}
+	}
+	return models.GroupRepository{}, false
+}
@@ -0,0 +1,84 @@
+package active
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdReportActive(tool *util.CmdTool) *cobra.Command {
+	var window string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "active [<group name>]",
+		Short: "List the repositories with the most recent commits",
+		Long: heredoc.Doc(`
+			List the repositories of a group with commits in the window, most commits
+			first. Defaults to the active group.
+
+			Commits are taken from those recorded by 'rr group ingest', so ingest the group
+			first. The window is a number followed by d, w, m or y for days, weeks, months
+			or years.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group report active backend
+			$ rr group report active backend --window 3m --limit 5
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, err := util.WindowStart(window, time.Now())
+			if err != nil {
+				return util.FlagErrorWrap(err)
+			}
+			if limit < 0 {
+				return util.FlagErrorf("--limit cannot be negative")
+			}
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.MostActiveRepositories(storage.CommitFilter{Group: group, Since: since})
+			if err != nil {
+				return err
+			}
+			if len(repos) == 0 {
+				return util.NewNoResultsError(fmt.Sprintf("no commits recorded for %s since %s; run 'rr group ingest %s' to record them", group, since.Format(time.DateOnly), group))
+			}
+			if limit > 0 && len(repos) > limit {
+				repos = repos[:limit]
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("repo", "commits", "insertions", "deletions", "last commit")
+			for _, repo := range repos {
+				tp.AddField(repo.Name, cs.Bold)
+				tp.AddField(strconv.Itoa(repo.ActivityCount), nil)
+				tp.AddField("+"+strconv.Itoa(repo.Insertions), cs.Green)
+				tp.AddField("-"+strconv.Itoa(repo.Deletions), cs.Red)
+				tp.AddField(repo.LastCommit.Local().Format(time.DateOnly), cs.Gray)
+				tp.EndRow()
+			}
+			return tp.Render()
+		},
+	}
+
+	cmd.Flags().StringVarP(&window, "window", "w", "30d", "Count commits in this window")
+	cmd.Flags().IntVarP(&limit, "limit", "L", 0, "Maximum number of repositories to list (default: all)")
+
+	return cmd
+}
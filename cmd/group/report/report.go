@@ -0,0 +1,29 @@
+package report
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	activeReportCmd "github.com/msetsma/RepoRover/cmd/group/report/active"
+	staleReportCmd "github.com/msetsma/RepoRover/cmd/group/report/stale"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupReport(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report <report>",
+		Short: "Report on the activity of the repositories in a group",
+		Long: heredoc.Doc(`
+			Report on the activity of the repositories in a group, based on the commits
+			recorded by 'rr group ingest'.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group report stale backend --window 6m
+			$ rr group report active backend --window 30d
+		`),
+	}
+
+	cmd.AddCommand(activeReportCmd.CmdReportActive(tool))
+	cmd.AddCommand(staleReportCmd.CmdReportStale(tool))
+
+	return cmd
+}
@@ -0,0 +1,83 @@
+package stale
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdReportStale(tool *util.CmdTool) *cobra.Command {
+	var window string
+
+	cmd := &cobra.Command{
+		Use:   "stale [<group name>]",
+		Short: "List repositories without recent commits",
+		Long: heredoc.Doc(`
+			List the repositories of a group whose latest commit is older than the window,
+			oldest first. Defaults to the active group.
+
+			The latest commit is taken from the commits recorded by 'rr group ingest', so
+			ingest the group first. Repositories without recorded commits are listed as
+			never ingested.
+
+			The window is a number followed by d, w, m or y for days, weeks, months or years.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group report stale backend
+			$ rr group report stale backend --window 1y
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			before, err := util.WindowStart(window, now)
+			if err != nil {
+				return util.FlagErrorWrap(err)
+			}
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.StaleRepositories(group, before)
+			if err != nil {
+				return err
+			}
+			if len(repos) == 0 {
+				return util.NewNoResultsError(fmt.Sprintf("every repository in %s has commits since %s", group, before.Format(time.DateOnly)))
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("repo", "last commit", "age", "commits")
+			for _, repo := range repos {
+				tp.AddField(repo.Name, cs.Bold)
+				if repo.LastCommit.IsZero() {
+					tp.AddField("never ingested", cs.Yellow)
+					tp.AddField("-", cs.Gray)
+					tp.AddField("-", cs.Gray)
+				} else {
+					tp.AddField(repo.LastCommit.Local().Format(time.DateOnly), nil)
+					tp.AddField(fmt.Sprintf("%d days", int(now.Sub(repo.LastCommit).Hours()/24)), cs.Yellow)
+					tp.AddField(strconv.Itoa(repo.ActivityCount), cs.Gray)
+				}
+				tp.EndRow()
+			}
+			return tp.Render()
+		},
+	}
+
+	cmd.Flags().StringVarP(&window, "window", "w", "6m", "List repositories without commits in this window")
+
+	return cmd
+}
@@ -0,0 +1,269 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/languages"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+
+	// Register the providers.
+	_ "github.com/msetsma/RepoRover/core/integrations/azure"
+	_ "github.com/msetsma/RepoRover/core/integrations/github"
+	_ "github.com/msetsma/RepoRover/core/integrations/gitlab"
+)
+
+type statsOptions struct {
+	Languages bool
+	Refresh   bool
+}
+
+func CmdGroupStats(tool *util.CmdTool) *cobra.Command {
+	opts := &statsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats [<group name>] --languages",
+		Short: "Show statistics of the repositories in a group",
+		Long: heredoc.Doc(`
+			Show statistics aggregated over the repositories of a group. Defaults to the
+			active group.
+
+			--languages sums the bytes of code per language. Languages are taken from the
+			provider a repository was imported from when it reports them, and otherwise
+			detected from the files tracked in the working tree; vendored and generated
+			files are not counted. When the provider fails the error is reported and the
+			working tree scanned. Results are stored and reused until --refresh is given.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group stats backend --languages
+			$ rr group stats backend --languages --refresh
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.Languages {
+				return util.FlagErrorf("specify the statistic to show, e.g. --languages")
+			}
+			return runLanguages(cmd, tool, opts, args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Languages, "languages", false, "Show the bytes of code per language")
+	cmd.Flags().BoolVar(&opts.Refresh, "refresh", false, "Collect the statistics again instead of using stored ones")
+
+	return cmd
+}
+
+// languageResult holds the languages of a single repository.
+type languageResult struct {
+	Repo      models.GroupRepository
+	Languages map[string]uint64
+	Source    string
+	// Fresh is set when the languages were collected now and need saving.
+	Fresh bool
+	// ProviderErr is set when the provider failed and the working tree was
+	// scanned instead.
+	ProviderErr error
+	Err         error
+}
+
+func runLanguages(cmd *cobra.Command, tool *util.CmdTool, opts *statsOptions, args []string) error {
+	cfg, err := tool.Config()
+	if err != nil {
+		return err
+	}
+	group, err := util.GroupArg(args, cfg)
+	if err != nil {
+		return err
+	}
+	settings, err := util.GroupSettings(cmd, cfg, group)
+	if err != nil {
+		return err
+	}
+	db, err := tool.Database()
+	if err != nil {
+		return err
+	}
+	repos, err := db.GroupRepositories(group)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return util.NewNoResultsError(fmt.Sprintf("group %s has no repositories", group))
+	}
+	known, err := db.GetRepositories()
+	if err != nil {
+		return err
+	}
+	metadata := make(map[string]models.Repository, len(known))
+	for _, repo := range known {
+		metadata[repo.ID] = repo
+	}
+
+	// Stored languages are read up front; the database is not touched from
+	// the workers.
+	results := make([]languageResult, len(repos))
+	var pending []int
+	for i, repo := range repos {
+		results[i].Repo = repo
+		if !opts.Refresh {
+			langs, source, err := db.RepositoryLanguages(repo.RepositoryID)
+			if err != nil {
+				return err
+			}
+			if len(langs) > 0 {
+				results[i].Languages, results[i].Source = langs, source
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+
+	// Providers that cannot be set up, e.g. for lack of a token, are skipped
+	// and their repositories scanned instead.
+	reporters := map[string]integrations.LanguageReporter{}
+	for _, i := range pending {
+		name := metadata[repos[i].RepositoryID].Provider
+		if _, seen := reporters[name]; seen || name == "" {
+			continue
+		}
+		reporters[name] = nil
+		if provider, err := integrations.New(name, cfg.Integrations); err == nil {
+			if reporter, ok := provider.(integrations.LanguageReporter); ok {
+				reporters[name] = reporter
+			}
+		}
+	}
+
+	collector := &collector{
+		reporters: reporters,
+		metadata:  metadata,
+		workspace: workspace.New(settings.CloneDestination, group),
+	}
+	_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Collecting languages of %s", group), func() error {
+		collected := workspace.Parallel(cmd.Context(), pending, settings.Concurrency, func(ctx context.Context, i int) languageResult {
+			return collector.collect(ctx, repos[i])
+		})
+		for n, i := range pending {
+			results[i] = collected[n]
+		}
+		return nil
+	})
+
+	for _, r := range results {
+		if r.Fresh && len(r.Languages) > 0 {
+			if err := db.SaveLanguages(r.Repo.RepositoryID, r.Source, r.Languages); err != nil {
+				return err
+			}
+		}
+		if r.ProviderErr != nil {
+			fmt.Fprintf(tool.IOStreams.ErrOut, "no languages from %s for %s: %v\n", metadata[r.Repo.RepositoryID].Provider, r.Repo.Name, r.ProviderErr)
+		}
+		if r.Err != nil {
+			fmt.Fprintf(tool.IOStreams.ErrOut, "no languages for %s: %v\n", r.Repo.Name, r.Err)
+		}
+	}
+
+	return printLanguages(tool, results)
+}
+
+// collector gathers the languages of repositories, asking the provider first
+// and scanning the working tree otherwise.
+type collector struct {
+	reporters map[string]integrations.LanguageReporter
+	metadata  map[string]models.Repository
+	workspace workspace.Workspace
+}
+
+func (c *collector) collect(ctx context.Context, repo models.GroupRepository) languageResult {
+	result := languageResult{Repo: repo, Fresh: true}
+
+	meta := c.metadata[repo.RepositoryID]
+	if reporter := c.reporters[meta.Provider]; reporter != nil {
+		langs, err := reporter.Languages(ctx, meta)
+		if err != nil {
+			result.ProviderErr = err
+		} else if len(langs) > 0 {
+			result.Languages, result.Source = langs, meta.Provider
+			return result
+		}
+	}
+
+	dir := c.workspace.RepoDir(repo)
+	if !git.IsRepository(ctx, dir) {
+		result.Err = fmt.Errorf("not cloned")
+		return result
+	}
+	result.Languages, result.Err = languages.Scan(ctx, dir)
+	result.Source = storage.LanguageSourceScan
+	return result
+}
+
+func printLanguages(tool *util.CmdTool, results []languageResult) error {
+	type total struct {
+		language string
+		bytes    uint64
+		repos    int
+	}
+	totals := map[string]*total{}
+	var sum uint64
+	for _, r := range results {
+		for lang, bytes := range r.Languages {
+			t, ok := totals[lang]
+			if !ok {
+				t = &total{language: lang}
+				totals[lang] = t
+			}
+			t.bytes += bytes
+			t.repos++
+			sum += bytes
+		}
+	}
+	if sum == 0 {
+		return util.NewNoResultsError("no language data found")
+	}
+
+	sorted := make([]*total, 0, len(totals))
+	for _, t := range totals {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].bytes != sorted[j].bytes {
+			return sorted[i].bytes > sorted[j].bytes
+		}
+		return sorted[i].language < sorted[j].language
+	})
+
+	cs := tool.IOStreams.ColorScheme()
+	tp := util.NewTablePrinter(tool.IOStreams.Out)
+	tp.AddHeader("language", "bytes", "share", "repos")
+	for _, t := range sorted {
+		tp.AddField(t.language, cs.Bold)
+		tp.AddField(formatBytes(t.bytes), nil)
+		tp.AddField(fmt.Sprintf("%.1f%%", float64(t.bytes)*100/float64(sum)), cs.Cyan)
+		tp.AddField(strconv.Itoa(t.repos), cs.Gray)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
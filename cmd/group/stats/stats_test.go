@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/workspace"
+)
+
+// fakeReporter reports the same languages, or error, for every repository.
+type fakeReporter struct {
+	langs map[string]uint64
+	err   error
+}
+
+func (f fakeReporter) Languages(ctx context.Context, repo models.Repository) (map[string]uint64, error) {
+	return f.langs, f.err
+}
+
+func TestCollect(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	ctx := context.Background()
+	ws := workspace.New(t.TempDir(), "backend")
+
+	// cloned has a single Go file in its working tree.
+	cloned := filepath.Join(ws.Dir(), "cloned")
+	if err := os.MkdirAll(cloned, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cloned, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "main.go"}} {
+		if _, err := git.Run(ctx, cloned, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scanned := map[string]uint64{"Go": 13}
+	reported := map[string]uint64{"Rust": 1000}
+	errRateLimited := errors.New("rate limited")
+
+	tests := []struct {
+		name            string
+		repo            string
+		reporter        integrations.LanguageReporter
+		wantLanguages   map[string]uint64
+		wantSource      string
+		wantProviderErr error
+		wantErr         bool
+	}{
+		{name: "provider", repo: "cloned", reporter: fakeReporter{langs: reported}, wantLanguages: reported, wantSource: "github"},
+		{name: "provider without languages", repo: "cloned", reporter: fakeReporter{}, wantLanguages: scanned, wantSource: storage.LanguageSourceScan},
+		{name: "provider error", repo: "cloned", reporter: fakeReporter{err: errRateLimited}, wantLanguages: scanned, wantSource: storage.LanguageSourceScan, wantProviderErr: errRateLimited},
+		{name: "no provider", repo: "cloned", wantLanguages: scanned, wantSource: storage.LanguageSourceScan},
+		{name: "not cloned", repo: "missing", wantErr: true},
+		{name: "provider error and not cloned", repo: "missing", reporter: fakeReporter{err: errRateLimited}, wantProviderErr: errRateLimited, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &collector{
+				reporters: map[string]integrations.LanguageReporter{"github": tt.reporter},
+				metadata:  map[string]models.Repository{tt.repo: {ID: tt.repo, Name: tt.repo, Provider: "github"}},
+				workspace: ws,
+			}
+			got := c.collect(ctx, models.GroupRepository{RepositoryID: tt.repo, Name: tt.repo})
+			if !got.Fresh {
+				t.Error("result is not fresh")
+			}
+			if (got.Err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", got.Err, tt.wantErr)
+			}
+			if !errors.Is(got.ProviderErr, tt.wantProviderErr) {
+				t.Errorf("provider error = %v, want %v", got.ProviderErr, tt.wantProviderErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Languages, tt.wantLanguages) || got.Source != tt.wantSource {
+				t.Errorf("languages = %v from %q, want %v from %q", got.Languages, got.Source, tt.wantLanguages, tt.wantSource)
+			}
+		})
+	}
+}
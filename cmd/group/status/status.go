@@ -0,0 +1,116 @@
+package status
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupStatus(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [<group name>]",
+		Short: "Show the status of every repository in a group",
+		Long: heredoc.Doc(`
+			Show the current branch, local changes, ahead/behind counts against the upstream
+			and stash entries of every repository in a group. Defaults to the active group.
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			settings, err := util.GroupSettings(cmd, cfg, group)
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			ws := workspace.New(settings.CloneDestination, group)
+			var results []workspace.StatusResult
+			_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Reading status of %s", group), func() error {
+				results = ws.Status(cmd.Context(), repos, settings.Concurrency)
+				return nil
+			})
+
+			return printStatus(tool, results, settings.DefaultBranch)
+		},
+	}
+
+	return cmd
+}
+
+func printStatus(tool *util.CmdTool, results []workspace.StatusResult, defaultBranch string) error {
+	cs := tool.IOStreams.ColorScheme()
+	tp := util.NewTablePrinter(tool.IOStreams.Out)
+	tp.AddHeader("repo", "branch", "default", "staged", "unstaged", "untracked", "ahead", "behind", "stash")
+
+	for _, r := range results {
+		tp.AddField(r.Repo.Name, cs.Bold)
+		switch {
+		case r.Err != nil:
+			tp.AddField("error: "+r.Err.Error(), cs.Red)
+			tp.EndRow()
+			continue
+		case r.Status == nil:
+			tp.AddField("not cloned", cs.Yellow)
+			tp.EndRow()
+			continue
+		}
+
+		s := r.Status
+		branch := s.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		if s.Branch == defaultBranch {
+			tp.AddField(branch, cs.Cyan)
+			tp.AddField("yes", cs.Gray)
+		} else {
+			tp.AddField(branch, cs.Yellow)
+			tp.AddField("no", cs.Yellow)
+		}
+
+		tp.AddField(count(s.Staged), highlight(s.Staged, cs.Green))
+		tp.AddField(count(s.Unstaged), highlight(s.Unstaged, cs.Red))
+		tp.AddField(count(s.Untracked), highlight(s.Untracked, cs.Red))
+		if s.Upstream == "" {
+			tp.AddField("-", cs.Gray)
+			tp.AddField("-", cs.Gray)
+		} else {
+			tp.AddField(count(s.Ahead), highlight(s.Ahead, cs.Cyan))
+			tp.AddField(count(s.Behind), highlight(s.Behind, cs.Yellow))
+		}
+		tp.AddField(count(s.Stashes), highlight(s.Stashes, cs.Yellow))
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+func count(n int) string {
+	return strconv.Itoa(n)
+}
+
+// highlight returns color for non-zero counts so clean repositories stay quiet.
+func highlight(n int, color func(string) string) func(string) string {
+	if n == 0 {
+		return nil
+	}
+	return color
+}
@@ -1,151 +1,117 @@
-package cmd
-
-// import (
-// 	"fmt"
-// 	"os"
-// 	"sync"
-
-// 	"github.com/msetsma/RepoRover/core"
-// 	"github.com/msetsma/RepoRover/models"
-// 	"github.com/spf13/cobra"
-// )
-
-// var (
-// 	org     string
-// 	project string
-// 	pat     string
-// )
-
-// var fetchCmd = &cobra.Command{
-// 	Use:   "fetch",
-// 	Short: "Fetch repositories from Azure DevOps",
-// 	Run:   runFetchCommand,
-// }
-
-// func init() {
-// 	// Register flags
-// 	fetchCmd.Flags().StringVar(&org, "org", "", "Azure DevOps organization")
-// 	fetchCmd.Flags().StringVar(&project, "project", "", "Azure DevOps project name")
-// 	fetchCmd.Flags().StringVar(&pat, "pat", "", "Azure DevOps personal access token")
-
-// 	// Add fetchCmd to rootCmd
-// 	rootCmd.AddCommand(fetchCmd)
-// }
-
-// // runFetchCommand orchestrates the fetch process
-// func runFetchCommand(cmd *cobra.Command, args []string) {
-// 	dbName, dbConfig := loadAndValidateConfig()
-// 	db := initializeDatabase(dbName)
-// 	defer db.Close()
-
-// 	repos := fetchRepositories(dbConfig)
-// 	processRepositoryDetails(repos, db, dbConfig)
-// }
-
-// // loadAndValidateConfig loads the configuration, validates required fields, and merges with flags.
-// func loadAndValidateConfig() (string, map[string]string) {
-// 	// Load configuration
-// 	config, err := core.LoadConfig()
-// 	if err != nil {
-// 		fmt.Printf("Error loading configuration: %v\n", err)
-// 		os.Exit(1)
-// 	}
-
-// 	// Determine the database name
-// 	dbFlagValue := rootCmd.PersistentFlags().Lookup("db").Value.String()
-// 	dbName := core.GetDatabaseName(dbFlagValue, "default")
-
-// 	// Get database-specific config
-// 	dbConfig := config.Databases[dbName]
-// 	if dbConfig == nil {
-// 		dbConfig = make(map[string]string)
-// 	}
-
-// 	// Merge flags with config
-// 	if org != "" {
-// 		dbConfig["azure_org"] = org
-// 	}
-// 	if project != "" {
-// 		dbConfig["azure_project"] = project
-// 	}
-// 	if pat != "" {
-// 		dbConfig["azure_pat"] = pat
-// 	}
-
-// 	// Validate required fields
-// 	if dbConfig["azure_org"] == "" || dbConfig["azure_project"] == "" || dbConfig["azure_pat"] == "" {
-// 		fmt.Println("Error: Missing required arguments --org, --project, or --pat.")
-// 		fmt.Println("Please set them using `reporover config set` or provide them as flags.")
-// 		os.Exit(1)
-// 	}
-
-// 	// Save updated config
-// 	config.Databases[dbName] = dbConfig
-// 	if err := core.SaveConfig(config); err != nil {
-// 		fmt.Printf("Error saving configuration: %v\n", err)
-// 		os.Exit(1)
-// 	}
-
-// 	return dbName, dbConfig
-// }
-
-// // initializeDatabase ensures the database is initialized and ready for use
-// func initializeDatabase(dbName string) *core.Database {
-// 	db, err := core.GetDatabaseInstance(dbName)
-// 	if err != nil {
-// 		fmt.Printf("Error initializing database: %v\n", err)
-// 		os.Exit(1)
-// 	}
-// 	return db
-// }
-
-// // fetchRepositories fetches the list of repositories from Azure DevOps
-// func fetchRepositories(config map[string]string) []models.Repository {
-// 	fmt.Printf("Fetching repositories for organization: %s, project: %s\n", config["azure_org"], config["azure_project"])
-// 	repos, err := core.FetchRepositories(config["azure_org"], config["azure_project"], config["azure_pat"])
-// 	if err != nil {
-// 		fmt.Printf("Error fetching repositories: %v\n", err)
-// 		os.Exit(1)
-// 	}
-// 	return repos
-// }
-
-// // processRepositoryDetails fetches additional details and saves them to the database
-// func processRepositoryDetails(repos []models.Repository, db *core.Database, config map[string]string) {
-// 	var wg sync.WaitGroup
-// 	repoChan := make(chan *models.FetchResult, len(repos))
-
-// 	// Fetch additional data concurrently
-// 	for _, repo := range repos {
-// 		wg.Add(1)
-// 		go func(repo models.Repository) {
-// 			defer wg.Done()
-// 			fullRepo, err := core.FetchAdditionalRepoData(config["azure_org"], config["azure_project"], config["azure_pat"], repo.ID)
-// 			repoChan <- &models.FetchResult{
-// 				Repo: fullRepo,
-// 				Err:  err,
-// 			}
-// 		}(repo)
-// 	}
-
-// 	// Close the channel after all Goroutines finish
-// 	go func() {
-// 		wg.Wait()
-// 		close(repoChan)
-// 	}()
-
-// 	// Process results
-// 	for result := range repoChan {
-// 		if result.Err != nil {
-// 			fmt.Printf("Error fetching details for repo %s: %v\n", result.Repo.Name, result.Err)
-// 			continue
-// 		}
-
-// 		err := db.SaveRepository(result.Repo)
-// 		if err != nil {
-// 			fmt.Printf("Error saving repository %s: %v\n", result.Repo.Name, err)
-// 		} else {
-// 			fmt.Printf("Saved repository: %s\n", result.Repo.Name)
-// 		}
-// 	}
-// }
+package sync
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/msetsma/RepoRover/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+func CmdGroupSync(tool *util.CmdTool) *cobra.Command {
+	var branch string
+
+	cmd := &cobra.Command{
+		Use:   "sync [<group name>]",
+		Short: "Check out and fast-forward a branch in every repository of a group",
+		Long: heredoc.Doc(`
+			Fetch every repository of a group, check out the requested branch and
+			fast-forward it to origin. A tracking branch is created when only origin has
+			the branch. Without --branch the default branch of the group is used.
+
+			Repositories where the branch does not exist are reported and skipped. A branch
+			with local commits that origin doesn't have is reported as ahead and left as it
+			is.
+		`),
+		Example: heredoc.Doc(`
+			$ rr group sync backend --branch develop
+			$ rr group sync
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			group, err := util.GroupArg(args, cfg)
+			if err != nil {
+				return err
+			}
+			settings, err := util.GroupSettings(cmd, cfg, group)
+			if err != nil {
+				return err
+			}
+			if branch != "" {
+				if err := settings.Override("default_branch", branch); err != nil {
+					return err
+				}
+			}
+			branch := settings.DefaultBranch
+			if err := git.CheckBranchName(cmd.Context(), branch); err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			ws := workspace.New(settings.CloneDestination, group)
+			ws.Credentials = git.Credentials(settings.Credentials)
+			var results []workspace.SyncResult
+			_ = tool.IOStreams.RunWithProgress(fmt.Sprintf("Syncing %s to %s", group, branch), func() error {
+				results = ws.Sync(cmd.Context(), repos, branch, settings.Concurrency)
+				return nil
+			})
+
+			return printResults(tool, results)
+		},
+	}
+
+	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch to check out (default: the default branch of the group)")
+
+	return cmd
+}
+
+func printResults(tool *util.CmdTool, results []workspace.SyncResult) error {
+	cs := tool.IOStreams.ColorScheme()
+	tp := util.NewTablePrinter(tool.IOStreams.Out)
+	tp.AddHeader("repo", "branch", "old", "new", "outcome")
+
+	var failed []workspace.SyncResult
+	for _, r := range results {
+		tp.AddField(r.Repo.Name, cs.Bold)
+		tp.AddField(r.Branch, cs.Cyan)
+		tp.AddField(git.ShortSHA(r.OldSHA), cs.Gray)
+		tp.AddField(git.ShortSHA(r.NewSHA), cs.Gray)
+		switch {
+		case r.Outcome.Failed():
+			tp.AddField(string(r.Outcome), cs.Red)
+			failed = append(failed, r)
+		case r.Outcome == workspace.SyncUpdated || r.Outcome == workspace.SyncCreated:
+			tp.AddField(string(r.Outcome), cs.Green)
+		case r.Outcome == workspace.SyncNoBranch || r.Outcome == workspace.SyncNotCloned:
+			tp.AddField(string(r.Outcome), cs.Yellow)
+		default:
+			tp.AddField(string(r.Outcome), nil)
+		}
+		tp.EndRow()
+	}
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	fmt.Fprintf(tool.IOStreams.ErrOut, "\n%d of %d repositories failed to sync:\n", len(failed), len(results))
+	for _, r := range failed {
+		fmt.Fprintf(tool.IOStreams.ErrOut, "  %s: %v\n", r.Repo.Name, r.Err)
+	}
+	return util.ErrSilent
+}
@@ -1,9 +1,20 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
 	"github.com/MakeNowJust/heredoc"
+	CmdAlias "github.com/msetsma/RepoRover/cmd/alias"
+	"github.com/msetsma/RepoRover/cmd/alias/expand"
 	CmdConfig "github.com/msetsma/RepoRover/cmd/config"
+	CmdDB "github.com/msetsma/RepoRover/cmd/db"
+	CmdDoctor "github.com/msetsma/RepoRover/cmd/doctor"
 	CmdGroup "github.com/msetsma/RepoRover/cmd/group"
+	CmdTemplate "github.com/msetsma/RepoRover/cmd/template"
+	"github.com/msetsma/RepoRover/core/integrations"
 	"github.com/msetsma/RepoRover/core/util"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +44,9 @@ func CmdRoot(tool *util.CmdTool) (*cobra.Command, error) {
 
 	cmd.SilenceErrors = true
 	cmd.SilenceUsage = true
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return util.FlagErrorWrap(err)
+	})
 
 	cmd.AddGroup(&cobra.Group{
 		ID:    "config",
@@ -53,6 +67,10 @@ func CmdRoot(tool *util.CmdTool) (*cobra.Command, error) {
 	// Example adding commands
 	cmd.AddCommand(CmdConfig.NewCmdConfig(tool))
 	cmd.AddCommand(CmdGroup.NewCmdGroup(tool))
+	cmd.AddCommand(CmdAlias.NewCmdAlias(tool))
+	cmd.AddCommand(CmdTemplate.NewCmdTemplate(tool))
+	cmd.AddCommand(CmdDB.NewCmdDB(tool))
+	cmd.AddCommand(CmdDoctor.NewCmdDoctor(tool))
 
 	//
 
@@ -64,9 +82,94 @@ func Run(tool *util.CmdTool) exitCode {
 	if err != nil {
 		return exitError
 	}
-	err = root.Execute()
+
+	// Built-in commands always win over aliases.
+	args := os.Args[1:]
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	if len(args) > 0 && !hasCommand(root, args) {
+		cfg, err := tool.Config()
+		if err != nil {
+			return handleError(tool, root, err)
+		}
+		expanded, isShell, err := expand.ExpandAlias(cfg.Aliases, os.Args)
+		if err != nil {
+			return handleError(tool, root, err)
+		}
+		if isShell {
+			return runShellAlias(tool, expanded)
+		}
+		args = expanded
+	}
+	root.SetArgs(args)
+
+	cmd, err := root.ExecuteC()
 	if err != nil {
+		return handleError(tool, cmd, err)
+	}
+	return exitOK
+}
+
+// hasCommand reports whether args start with a built-in command.
+func hasCommand(root *cobra.Command, args []string) bool {
+	c, _, err := root.Traverse(args)
+	return err == nil && c != root
+}
+
+// runShellAlias runs an expanded shell alias attached to the terminal and
+// passes its exit code through.
+func runShellAlias(tool *util.CmdTool, args []string) exitCode {
+	external := exec.Command(args[0], args[1:]...)
+	external.Stdin = tool.IOStreams.In
+	external.Stdout = tool.IOStreams.Out
+	external.Stderr = tool.IOStreams.ErrOut
+	if err := external.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitCode(exitErr.ExitCode())
+		}
+		fmt.Fprintf(tool.IOStreams.ErrOut, "failed to run shell alias: %v\n", err)
 		return exitError
 	}
 	return exitOK
 }
+
+// handleError reports err on stderr and maps it onto an exit code.
+func handleError(tool *util.CmdTool, cmd *cobra.Command, err error) exitCode {
+	stderr := tool.IOStreams.ErrOut
+
+	switch {
+	case errors.Is(err, util.ErrSilent):
+		return exitError
+	case errors.Is(err, util.ErrCancel):
+		return exitCancel
+	case errors.Is(err, util.ErrPending):
+		return exitPending
+	}
+
+	var noResults util.NoResultsError
+	if errors.As(err, &noResults) {
+		fmt.Fprintln(stderr, noResults.Error())
+		return exitOK
+	}
+
+	var exitErr *util.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr.Code)
+	}
+
+	fmt.Fprintln(stderr, err)
+
+	if errors.Is(err, integrations.ErrUnauthorized) {
+		return exitAuth
+	}
+
+	var flagError *util.FlagError
+	if errors.As(err, &flagError) {
+		if cmd != nil {
+			fmt.Fprintln(stderr)
+			fmt.Fprintln(stderr, cmd.UsageString())
+		}
+	}
+	return exitError
+}
@@ -0,0 +1,48 @@
+package list
+
+import (
+	"strconv"
+
+	"github.com/msetsma/RepoRover/core/templates"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdListTemplates(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List templates",
+		Aliases: []string{"ls"},
+		Args:    util.NoArgsQuoteReminder,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := templates.List()
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				return util.NewNoResultsError("no templates found in " + templates.Dir())
+			}
+
+			cs := tool.IOStreams.ColorScheme()
+			tp := util.NewTablePrinter(tool.IOStreams.Out)
+			tp.AddHeader("template", "repos", "description")
+			for _, name := range names {
+				t, err := templates.Load(name)
+				if err != nil {
+					tp.AddField(name, cs.Bold)
+					tp.AddField("", nil)
+					tp.AddField(err.Error(), cs.Red)
+					tp.EndRow()
+					continue
+				}
+				tp.AddField(name, cs.Bold)
+				tp.AddField(strconv.Itoa(len(t.Repositories)), nil)
+				tp.AddField(t.Description, cs.Gray)
+				tp.EndRow()
+			}
+			return tp.Render()
+		},
+	}
+
+	return cmd
+}
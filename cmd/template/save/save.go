@@ -0,0 +1,76 @@
+package save
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/templates"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+)
+
+func CmdSaveTemplate(tool *util.CmdTool) *cobra.Command {
+	var (
+		vars        map[string]string
+		description string
+		force       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save <group name> <template>",
+		Short: "Save a group as a template",
+		Long: heredoc.Doc(`
+			Save the repositories and group-specific settings of a group as a template.
+			Repositories without a remote cannot be part of a template and are skipped.
+
+			Values given with --var are replaced by placeholders in repository names and
+			URLs, e.g. --var Org=acme turns https://github.com/acme/api into
+			https://github.com/{{ .Org }}/api.
+		`),
+		Args: util.ExactArgs(2, "requires a group name and a template name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group, name := args[0], args[1]
+			if templates.Exists(name) && !force {
+				return fmt.Errorf("template '%s' already exists; use --force to replace it", name)
+			}
+
+			cfg, err := tool.Config()
+			if err != nil {
+				return err
+			}
+			db, err := tool.Database()
+			if err != nil {
+				return err
+			}
+			repos, err := db.GroupRepositories(group)
+			if err != nil {
+				return err
+			}
+
+			t := &templates.Template{
+				Description: description,
+				Overrides:   templates.CaptureOverrides(cfg.Groups[group]),
+			}
+			for _, repo := range repos {
+				if repo.RemoteURL == "" {
+					fmt.Fprintf(tool.IOStreams.ErrOut, "skipping %s: it has no remote\n", repo.Name)
+					continue
+				}
+				t.Repositories = append(t.Repositories, templates.Repository{Name: repo.Name, URL: repo.RemoteURL})
+			}
+			t.Parameterize(vars)
+
+			if err := templates.Save(name, t); err != nil {
+				return err
+			}
+			fmt.Fprintf(tool.IOStreams.Out, "Saved template %s with %d repositories\n", name, len(t.Repositories))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringToStringVar(&vars, "var", nil, "Replace `name=value` in names and URLs with a {{ .name }} placeholder")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Description of the template")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an existing template")
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package template
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/msetsma/RepoRover/core/util"
+	"github.com/spf13/cobra"
+
+	listTemplateCmd "github.com/msetsma/RepoRover/cmd/template/list"
+	saveTemplateCmd "github.com/msetsma/RepoRover/cmd/template/save"
+)
+
+func NewCmdTemplate(tool *util.CmdTool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template <command>",
+		Short: "Manage group templates",
+		Long: heredoc.Doc(`
+			Templates capture the repositories and group-specific settings of a group so new
+			groups can be bootstrapped from them. Templates are YAML files in the templates
+			directory of the config directory; URLs may use placeholders such as {{ .Org }}
+			that are filled in with --var when a group is created.
+		`),
+		Example: heredoc.Doc(`
+			$ rr template save backend backend-team --var Org=acme
+			$ rr group init my-backend --template backend-team --var Org=acme
+		`),
+		GroupID: "group",
+	}
+
+	cmd.AddCommand(saveTemplateCmd.CmdSaveTemplate(tool))
+	cmd.AddCommand(listTemplateCmd.CmdListTemplates(tool))
+
+	return cmd
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/msetsma/RepoRover/core/paths"
 	"github.com/spf13/viper"
 )
 
@@ -17,11 +20,28 @@ type Manifest struct {
 	Paths         Paths             `mapstructure:"paths"`
 	Credentials   Credentials       `mapstructure:"credentials"`
 	Integrations  Integrations      `mapstructure:"integrations"`
+	// Groups holds per-group overrides keyed by group name.
+	Groups map[string]GroupOverrides `mapstructure:"groups"`
+}
+
+// GroupOverrides are settings of a single group that take precedence over the
+// global ones. Keys that are not set are nil and the global value applies;
+// zero values do override.
+type GroupOverrides struct {
+	DefaultBranch    *string             `mapstructure:"default_branch,omitempty"`
+	Concurrency      *int                `mapstructure:"concurrency,omitempty"`
+	CloneDestination *string             `mapstructure:"clone_destination,omitempty"`
+	Credentials      CredentialOverrides `mapstructure:"credentials,omitempty"`
+}
+
+// CredentialOverrides are the credential settings of a single group.
+type CredentialOverrides struct {
+	Helper  *string `mapstructure:"helper,omitempty"`
+	Timeout *int    `mapstructure:"timeout,omitempty"`
 }
 
 type Paths struct {
 	Groups string `mapstructure:"clone_destination"`
-	Temp   string `mapstructure:"temp"`
 }
 
 type Credentials struct {
@@ -30,7 +50,9 @@ type Credentials struct {
 }
 
 type Integrations struct {
-	Azure Azure `mapstructure:"azure"`
+	Azure  Azure  `mapstructure:"azure"`
+	GitHub GitHub `mapstructure:"github"`
+	GitLab GitLab `mapstructure:"gitlab"`
 }
 
 type Azure struct {
@@ -39,83 +61,57 @@ type Azure struct {
 	APIToken string `mapstructure:"api_token"`
 }
 
-const ConfigFileName = "rover.yaml"
+// GitHub configures github.com or, with URL set to its API endpoint such as
+// https://github.example.com/api/v3, a GitHub Enterprise Server.
+type GitHub struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	URL      string `mapstructure:"url"`
+	APIToken string `mapstructure:"api_token"`
+}
 
-var DefaultBasePath = GetDefaultConfigDirectory()
-var DefaultConfigPath = filepath.Join(DefaultBasePath, ConfigFileName)
-var DefaultGroupsDirectory = filepath.Join(DefaultBasePath, "groups")
-var DefaultTempDirectory = filepath.Join(DefaultBasePath, "tmp")
+// GitLab configures gitlab.com or, with URL set to its address such as
+// https://gitlab.example.com, a self-managed instance.
+type GitLab struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	URL      string `mapstructure:"url"`
+	APIToken string `mapstructure:"api_token"`
+}
+
+// ConfigFileName is the name of the configuration file.
+const ConfigFileName = paths.ConfigFileName
 
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("active_group", "default")
 	v.SetDefault("default_branch", "main")
 	v.SetDefault("concurrency", 10)
-	v.SetDefault("paths.groups", DefaultGroupsDirectory)
-	v.SetDefault("paths.temp", DefaultTempDirectory)
+	locations := paths.Resolve()
+	v.SetDefault("paths.clone_destination", locations.Groups.Path)
 	v.SetDefault("credentials.helper", "cache")
 	v.SetDefault("credentials.timeout", 3600)
 	v.SetDefault("integrations.azure.enabled", false)
 	v.SetDefault("integrations.azure.url", "")
 	v.SetDefault("integrations.azure.api_token", "")
+	v.SetDefault("integrations.github.enabled", false)
+	v.SetDefault("integrations.github.url", "")
+	v.SetDefault("integrations.github.api_token", "")
+	v.SetDefault("integrations.gitlab.enabled", false)
+	v.SetDefault("integrations.gitlab.url", "")
+	v.SetDefault("integrations.gitlab.api_token", "")
 }
 
-func GetConfigLocation() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	locations := []string{}
-
-	// Only include XDG_CONFIG_HOME if it is set
-	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
-	if xdgConfig != "" {
-		locations = append(locations, filepath.Join(xdgConfig, "rover", ConfigFileName))
-	}
-
-	// Add fallback locations
-	locations = append(locations,
-		filepath.Join(homeDir, ".config", ConfigFileName),
-		filepath.Join(homeDir, ".config", "rover", ConfigFileName),
-	)
-
-	for _, path := range locations {
-		if _, err := os.Stat(path); err == nil {
-			return path
-		}
-	}
-
-	return ""
+// Location returns the path of the configuration file, which may not exist
+// yet.
+func Location() string {
+	return paths.Resolve().ConfigFile.Path
 }
 
-// GetDefaultConfigPath returns the default dir for the configuration files.
-func GetDefaultConfigDirectory() string {
-	homeDir := os.Getenv("HOME")
-	if homeDir == "" {
-		homeDir = os.Getenv("USERPROFILE")
-	}
-
-	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
-	if xdgConfig == "" {
-		xdgConfig = filepath.Join(homeDir, ".config")
-	}
-
-	xdgConfigPath := filepath.Join(xdgConfig, "rover")
-	configPath := filepath.Join(homeDir, ".config", "rover")
-
-	if _, err := os.Stat(xdgConfigPath); err == nil {
-		return xdgConfigPath
-	}
-	return configPath
-}
-
-func CreateConfigFile(v *viper.Viper) error {
+func CreateConfigFile(v *viper.Viper, configPath string) error {
 	// Create the configuration directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(DefaultConfigPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 	// Write default values to the config file
-	if err := v.WriteConfigAs(DefaultConfigPath); err != nil {
+	if err := v.WriteConfigAs(configPath); err != nil {
 		return fmt.Errorf("failed to write default config file: %w", err)
 	}
 	return nil
@@ -140,61 +136,46 @@ func expandEnvVariables(cfg interface{}) interface{} {
 	}
 }
 
-// Update saves the given Manifest struct to the configuration file.
-func Update(manifest *Manifest) error {
-	configPath := GetConfigLocation()
-
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	configMap := make(map[string]interface{})
-	err := mapstructure.Decode(manifest, &configMap)
-	if err != nil {
-		return fmt.Errorf("invalid config structure")
-	}
-
+func Load() (*Manifest, error) {
 	v := viper.New()
+	setDefaults(v)
+	configPath := Location()
 	v.SetConfigFile(configPath)
-	v.SetConfigType("yaml")
+	v.AutomaticEnv()
 
-	// Load the existing configuration if it exists
-	if _, err := os.Stat(configPath); err == nil {
-		if err := v.ReadInConfig(); err != nil {
-			return fmt.Errorf("failed to read existing config file")
+	if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+		if err := CreateConfigFile(v, configPath); err != nil {
+			return nil, err
 		}
+	} else if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
 	}
-
-	for key, value := range configMap {
-		v.Set(key, value)
-	}
-
-	if err := v.WriteConfigAs(configPath); err != nil {
-		return fmt.Errorf("failed to write updated config file")
-	}
-	return nil
+	return decode(v)
 }
 
-func Load() (*Manifest, error) {
+// Defaults returns the Manifest of the default values, without reading or
+// creating the configuration file.
+func Defaults() (*Manifest, error) {
 	v := viper.New()
 	setDefaults(v)
-	configPath := GetConfigLocation()
-	v.SetConfigFile(configPath)
 	v.AutomaticEnv()
+	return decode(v)
+}
 
-	if err := v.ReadInConfig(); err != nil {
-		fmt.Println(err)
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			CreateConfigFile(v)
-		}
-	}
-
+func decode(v *viper.Viper) (*Manifest, error) {
 	var rawConfig map[string]interface{}
 	if err := v.Unmarshal(&rawConfig); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config to map: %v", err)
 	}
 
-	expandedConfig := expandEnvVariables(rawConfig)
+	// Aliases are expanded later with their own $1, $2 placeholders, so they
+	// must not go through environment variable expansion.
+	aliases := rawConfig["aliases"]
+	delete(rawConfig, "aliases")
+	expandedConfig := expandEnvVariables(rawConfig).(map[string]interface{})
+	if aliases != nil {
+		expandedConfig["aliases"] = aliases
+	}
 	cfg := &Manifest{}
 	decoderConfig := &mapstructure.DecoderConfig{
 		Metadata: nil,
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Update stores value at path in the configuration file, e.g. [aliases up],
+// or, when value is nil, removes path so the default applies again. Only that
+// key changes: the rest of the file, including ${VAR} references and
+// comments, is written back as it was.
+func Update(path []string, value any) error {
+	configPath := Location()
+	doc, err := readDocument(configPath)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		removePath(doc.Content[0], path)
+	} else if err := setPath(doc.Content[0], path, value); err != nil {
+		return err
+	}
+	return writeDocument(configPath, doc)
+}
+
+func setPath(node *yaml.Node, path []string, value any) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %v: %w", value, err)
+	}
+	for _, key := range path[:len(path)-1] {
+		child := lookupNode(node, key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setNode(node, key, child)
+		}
+		node = child
+	}
+	setNode(node, path[len(path)-1], &encoded)
+	return nil
+}
+
+// removePath removes path below root. Mappings left empty are removed as
+// well.
+func removePath(root *yaml.Node, path []string) {
+	parents := []*yaml.Node{root}
+	for _, key := range path[:len(path)-1] {
+		child := lookupNode(parents[len(parents)-1], key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			return
+		}
+		parents = append(parents, child)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		removeNode(parents[i], path[i])
+		if i == 0 || len(parents[i].Content) > 0 {
+			break
+		}
+	}
+}
+
+// readDocument parses the file at path. A missing or empty file gives a
+// document with an empty mapping.
+func readDocument(path string) (*yaml.Node, error) {
+	doc := &yaml.Node{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s does not contain a mapping", path)
+	}
+	return doc, nil
+}
+
+func writeDocument(path string, doc *yaml.Node) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// lookupNode returns the value of key in the mapping node m, or nil.
+func lookupNode(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setNode(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			// Keep comments attached to the old value.
+			value.HeadComment, value.LineComment = m.Content[i+1].HeadComment, m.Content[i+1].LineComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func removeNode(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/paths"
+)
+
+func TestUpdate(t *testing.T) {
+	const original = `# rover configuration
+concurrency: 4 # per group
+integrations:
+  github:
+    api_token: ${GITHUB_TOKEN}
+aliases:
+  up: group pull --all
+`
+	tests := []struct {
+		name  string
+		path  []string
+		value any
+		want  string
+	}{
+		{
+			name:  "replace",
+			path:  []string{"concurrency"},
+			value: 8,
+			want: `# rover configuration
+concurrency: 8 # per group
+integrations:
+    github:
+        api_token: ${GITHUB_TOKEN}
+aliases:
+    up: group pull --all
+`,
+		},
+		{
+			name:  "add nested",
+			path:  []string{"groups", "legacy", "default_branch"},
+			value: "master",
+			want: `# rover configuration
+concurrency: 4 # per group
+integrations:
+    github:
+        api_token: ${GITHUB_TOKEN}
+aliases:
+    up: group pull --all
+groups:
+    legacy:
+        default_branch: master
+`,
+		},
+		{
+			name: "remove the last entry of a map",
+			path: []string{"aliases", "up"},
+			want: `# rover configuration
+concurrency: 4 # per group
+integrations:
+    github:
+        api_token: ${GITHUB_TOKEN}
+`,
+		},
+		{
+			name: "remove a missing key",
+			path: []string{"groups", "legacy", "concurrency"},
+			want: `# rover configuration
+concurrency: 4 # per group
+integrations:
+    github:
+        api_token: ${GITHUB_TOKEN}
+aliases:
+    up: group pull --all
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv(paths.HomeEnv, home)
+			file := filepath.Join(home, "config", ConfigFileName)
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte(original), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Update(tt.path, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("config file =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
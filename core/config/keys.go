@@ -0,0 +1,346 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// UnknownKeyError is returned for keys that are not part of the Manifest.
+type UnknownKeyError struct {
+	Key        string
+	Suggestion string
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown config key %q; did you mean %q?", e.Key, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown config key %q", e.Key)
+}
+
+// validators enforce constraints beyond the type of a key.
+var validators = map[string]func(any) error{
+	"concurrency": func(v any) error {
+		if v.(int) < 1 {
+			return fmt.Errorf("concurrency must be at least 1")
+		}
+		return nil
+	},
+	"credentials.timeout": func(v any) error {
+		if v.(int) < 0 {
+			return fmt.Errorf("credentials.timeout cannot be negative")
+		}
+		return nil
+	},
+}
+
+// schemaKey is a settable key derived from the mapstructure tags of a config
+// struct.
+type schemaKey struct {
+	name  string
+	index []int
+	// isMap is set for map fields whose entries are addressed as name.<entry>.
+	isMap bool
+}
+
+// schema lists every key of the struct type t. Maps are only settable when
+// their values are plain scalars.
+func schema(t reflect.Type) []schemaKey {
+	var keys []schemaKey
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			name := prefix + tag
+			idx := append(append([]int{}, index...), i)
+			switch f.Type.Kind() {
+			case reflect.Struct:
+				walk(f.Type, name+".", idx)
+			case reflect.Map:
+				if f.Type.Elem().Kind() != reflect.Struct {
+					keys = append(keys, schemaKey{name: name, index: idx, isMap: true})
+				}
+			default:
+				keys = append(keys, schemaKey{name: name, index: idx})
+			}
+		}
+	}
+	walk(t, "", nil)
+	return keys
+}
+
+// Keys returns every configuration key. Map keys are listed as name.<entry>.
+func Keys() []string {
+	return keyNames(reflect.TypeOf(Manifest{}))
+}
+
+// GroupKeys returns every key that can be overridden per group.
+func GroupKeys() []string {
+	return keyNames(reflect.TypeOf(GroupOverrides{}))
+}
+
+func keyNames(t reflect.Type) []string {
+	var names []string
+	for _, k := range schema(t) {
+		if k.isMap {
+			names = append(names, k.name+".<name>")
+		} else {
+			names = append(names, k.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup resolves a dotted key of the struct type t to its schema entry and,
+// for map entries, the entry name. Dashes may be used in place of
+// underscores, so default-branch and default_branch are the same key.
+func lookup(t reflect.Type, key string) (schemaKey, string, error) {
+	keys := schema(t)
+	for _, k := range keys {
+		for _, name := range []string{k.name, strings.ReplaceAll(k.name, "_", "-")} {
+			if key == name {
+				return k, "", nil
+			}
+			if k.isMap && strings.HasPrefix(key, name+".") && len(key) > len(name)+1 {
+				return k, key[len(name)+1:], nil
+			}
+		}
+	}
+	return schemaKey{}, "", &UnknownKeyError{Key: key, Suggestion: suggest(keys, key)}
+}
+
+// KeyPath returns the path of key in the configuration file, for use with
+// Update. Map entries are a single element, so alias names may contain
+// dots.
+func KeyPath(key string) ([]string, error) {
+	return keyPath(reflect.TypeOf(Manifest{}), key)
+}
+
+// GroupKeyPath returns the path of the override of key for group in the
+// configuration file.
+func GroupKeyPath(group, key string) ([]string, error) {
+	path, err := keyPath(reflect.TypeOf(GroupOverrides{}), key)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{"groups", group}, path...), nil
+}
+
+func keyPath(t reflect.Type, key string) ([]string, error) {
+	k, entry, err := lookup(t, key)
+	if err != nil {
+		return nil, err
+	}
+	path := strings.Split(k.name, ".")
+	if entry != "" {
+		path = append(path, entry)
+	}
+	return path, nil
+}
+
+// Get returns the value of key. Map keys without an entry return the whole map.
+func (m *Manifest) Get(key string) (any, error) {
+	return getKey(m, key)
+}
+
+// Set parses value according to the type of key and stores it.
+func (m *Manifest) Set(key, value string) error {
+	return setKey(m, key, value)
+}
+
+// Unset removes a map entry or restores a key to its default value.
+func (m *Manifest) Unset(key string) error {
+	return unsetKey(m, key, func(name string) any {
+		v := viper.New()
+		setDefaults(v)
+		return v.Get(name)
+	})
+}
+
+// Get returns the value of an overridden key, or nil when it is not
+// overridden.
+func (g *GroupOverrides) Get(key string) (any, error) {
+	return getKey(g, key)
+}
+
+// Set parses value according to the type of key and stores it.
+func (g *GroupOverrides) Set(key, value string) error {
+	return setKey(g, key, value)
+}
+
+// Unset removes an override so the global value applies again.
+func (g *GroupOverrides) Unset(key string) error {
+	return unsetKey(g, key, func(string) any { return nil })
+}
+
+func getKey(target any, key string) (any, error) {
+	v := reflect.ValueOf(target).Elem()
+	k, entry, err := lookup(v.Type(), key)
+	if err != nil {
+		return nil, err
+	}
+	field := v.FieldByIndex(k.index)
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil, nil
+		}
+		return field.Elem().Interface(), nil
+	}
+	if !k.isMap || entry == "" {
+		return field.Interface(), nil
+	}
+	value := field.MapIndex(reflect.ValueOf(entry))
+	if !value.IsValid() {
+		return nil, fmt.Errorf("%s is not set", key)
+	}
+	return value.Interface(), nil
+}
+
+func setKey(target any, key, value string) error {
+	v := reflect.ValueOf(target).Elem()
+	k, entry, err := lookup(v.Type(), key)
+	if err != nil {
+		return err
+	}
+	field := v.FieldByIndex(k.index)
+
+	if k.isMap {
+		if entry == "" {
+			return fmt.Errorf("%s is a map; set an entry with %s.<name>", key, key)
+		}
+		parsed, err := parse(key, field.Type().Elem(), value)
+		if err != nil {
+			return err
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(entry), parsed)
+		return nil
+	}
+
+	// Pointers are optional values, set to point at the parsed value.
+	t := field.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	parsed, err := parse(key, t, value)
+	if err != nil {
+		return err
+	}
+	if validate, ok := validators[k.name]; ok {
+		if err := validate(parsed.Interface()); err != nil {
+			return err
+		}
+	}
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(t)
+		ptr.Elem().Set(parsed)
+		parsed = ptr
+	}
+	field.Set(parsed)
+	return nil
+}
+
+// unsetKey removes a map entry or resets key to the value returned by
+// defaults, or to its zero value when there is no default.
+func unsetKey(target any, key string, defaults func(string) any) error {
+	v := reflect.ValueOf(target).Elem()
+	k, entry, err := lookup(v.Type(), key)
+	if err != nil {
+		return err
+	}
+	field := v.FieldByIndex(k.index)
+
+	if k.isMap {
+		if entry == "" {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		if !field.MapIndex(reflect.ValueOf(entry)).IsValid() {
+			return fmt.Errorf("%s is not set", key)
+		}
+		field.SetMapIndex(reflect.ValueOf(entry), reflect.Value{})
+		return nil
+	}
+
+	def := defaults(k.name)
+	if def == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	parsed, err := parse(key, field.Type(), fmt.Sprint(def))
+	if err != nil {
+		return err
+	}
+	field.Set(parsed)
+	return nil
+}
+
+func parse(key string, t reflect.Type, value string) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(value).Convert(t), nil
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s expects an integer, got %q", key, value)
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s expects true or false, got %q", key, value)
+		}
+		return reflect.ValueOf(b).Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("%s cannot be set from the command line", key)
+}
+
+// suggest returns the known key closest to key, if any is close enough to be
+// a plausible typo.
+func suggest(keys []schemaKey, key string) string {
+	best, bestDistance := "", len(key)/3+2
+	for _, k := range keys {
+		candidate := k.name
+		if k.isMap {
+			if i := strings.Index(key, "."); i >= 0 {
+				candidate = k.name + key[i:]
+			}
+		}
+		if d := levenshtein(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Origin tells where an effective setting came from.
+type Origin string
+
+// Origins in order of increasing precedence.
+const (
+	OriginDefault Origin = "default"
+	OriginGlobal  Origin = "global"
+	OriginGroup   Origin = "group"
+	OriginFlag    Origin = "flag"
+)
+
+// Setting is a single effective value along with its origin.
+type Setting struct {
+	Key    string
+	Value  any
+	Origin Origin
+}
+
+// Settings are the effective settings for working with one group.
+type Settings struct {
+	Group            string
+	DefaultBranch    string
+	Concurrency      int
+	CloneDestination string
+	Credentials      Credentials
+
+	origins map[string]Origin
+}
+
+// Resolve returns the effective settings for group. Values set on the group
+// take precedence over global ones, which in turn take precedence over the
+// built-in defaults. Flags are applied afterwards with Settings.Override.
+func (m *Manifest) Resolve(group string) *Settings {
+	s := &Settings{
+		Group:            group,
+		DefaultBranch:    m.DefaultBranch,
+		Concurrency:      m.Concurrency,
+		CloneDestination: m.Paths.Groups,
+		Credentials:      m.Credentials,
+		origins:          map[string]Origin{},
+	}
+
+	defaults := viper.New()
+	setDefaults(defaults)
+	global := func(key string, value any) {
+		if fmt.Sprint(defaults.Get(globalKeys[key])) == fmt.Sprint(value) {
+			s.origins[key] = OriginDefault
+		} else {
+			s.origins[key] = OriginGlobal
+		}
+	}
+	global("default_branch", s.DefaultBranch)
+	global("concurrency", s.Concurrency)
+	global("clone_destination", s.CloneDestination)
+	global("credentials.helper", s.Credentials.Helper)
+	global("credentials.timeout", s.Credentials.Timeout)
+
+	o, ok := m.Groups[group]
+	if !ok {
+		return s
+	}
+	if o.DefaultBranch != nil {
+		s.DefaultBranch, s.origins["default_branch"] = *o.DefaultBranch, OriginGroup
+	}
+	if o.Concurrency != nil {
+		s.Concurrency, s.origins["concurrency"] = *o.Concurrency, OriginGroup
+	}
+	if o.CloneDestination != nil {
+		s.CloneDestination, s.origins["clone_destination"] = *o.CloneDestination, OriginGroup
+	}
+	if o.Credentials.Helper != nil {
+		s.Credentials.Helper, s.origins["credentials.helper"] = *o.Credentials.Helper, OriginGroup
+	}
+	if o.Credentials.Timeout != nil {
+		s.Credentials.Timeout, s.origins["credentials.timeout"] = *o.Credentials.Timeout, OriginGroup
+	}
+	return s
+}
+
+// globalKeys maps group-level keys onto the global keys they override.
+var globalKeys = map[string]string{
+	"default_branch":      "default_branch",
+	"concurrency":         "concurrency",
+	"clone_destination":   "paths.clone_destination",
+	"credentials.helper":  "credentials.helper",
+	"credentials.timeout": "credentials.timeout",
+}
+
+// Override applies a value given on the command line. Only the keys of
+// GroupOverrides can be overridden.
+func (s *Settings) Override(key string, value any) error {
+	switch key {
+	case "default_branch":
+		s.DefaultBranch = value.(string)
+	case "concurrency":
+		s.Concurrency = value.(int)
+	case "clone_destination":
+		s.CloneDestination = value.(string)
+	case "credentials.helper":
+		s.Credentials.Helper = value.(string)
+	case "credentials.timeout":
+		s.Credentials.Timeout = value.(int)
+	default:
+		return fmt.Errorf("%s cannot be overridden", key)
+	}
+	s.origins[key] = OriginFlag
+	return nil
+}
+
+// List returns every effective setting along with its origin.
+func (s *Settings) List() []Setting {
+	return []Setting{
+		{"default_branch", s.DefaultBranch, s.origins["default_branch"]},
+		{"concurrency", s.Concurrency, s.origins["concurrency"]},
+		{"clone_destination", s.CloneDestination, s.origins["clone_destination"]},
+		{"credentials.helper", s.Credentials.Helper, s.origins["credentials.helper"]},
+		{"credentials.timeout", s.Credentials.Timeout, s.origins["credentials.timeout"]},
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/paths"
+)
+
+// loadConfig loads content as the configuration file.
+func loadConfig(t *testing.T, content string) *Manifest {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv(paths.HomeEnv, home)
+	file := filepath.Join(home, "config", ConfigFileName)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestResolve(t *testing.T) {
+	cfg := loadConfig(t, `
+concurrency: 4
+credentials:
+  timeout: 30
+groups:
+  legacy:
+    default_branch: master
+    concurrency: 2
+    credentials:
+      helper: ""
+      timeout: 0
+`)
+	defaultDestination := filepath.Join(os.Getenv(paths.HomeEnv), "data", "groups")
+
+	tests := []struct {
+		group string
+		want  []Setting
+	}{
+		{
+			group: "other",
+			want: []Setting{
+				{"default_branch", "main", OriginDefault},
+				{"concurrency", 4, OriginGlobal},
+				{"clone_destination", defaultDestination, OriginDefault},
+				{"credentials.helper", "cache", OriginDefault},
+				{"credentials.timeout", 30, OriginGlobal},
+			},
+		},
+		{
+			// Zero values set on the group override the global ones.
+			group: "legacy",
+			want: []Setting{
+				{"default_branch", "master", OriginGroup},
+				{"concurrency", 2, OriginGroup},
+				{"clone_destination", defaultDestination, OriginDefault},
+				{"credentials.helper", "", OriginGroup},
+				{"credentials.timeout", 0, OriginGroup},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			got := cfg.Resolve(tt.group).List()
+			if len(got) != len(tt.want) {
+				t.Fatalf("settings = %+v", got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("setting %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSettingsOverride(t *testing.T) {
+	cfg := loadConfig(t, `
+groups:
+  legacy:
+    concurrency: 2
+`)
+	s := cfg.Resolve("legacy")
+	if err := s.Override("concurrency", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Override("credentials.timeout", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Override("active_group", "x"); err == nil {
+		t.Error("overriding active_group succeeded")
+	}
+	if s.Concurrency != 1 || s.origins["concurrency"] != OriginFlag {
+		t.Errorf("concurrency = %d from %s, want 1 from %s", s.Concurrency, s.origins["concurrency"], OriginFlag)
+	}
+	if s.Credentials.Timeout != 0 || s.origins["credentials.timeout"] != OriginFlag {
+		t.Errorf("credentials.timeout = %d from %s, want 0 from %s", s.Credentials.Timeout, s.origins["credentials.timeout"], OriginFlag)
+	}
+}
+
+func TestGroupOverrides(t *testing.T) {
+	var o GroupOverrides
+	if v, err := o.Get("credentials.timeout"); err != nil || v != nil {
+		t.Errorf("before setting: %v, %v; want nil", v, err)
+	}
+	if err := o.Set("credentials.timeout", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := o.Get("credentials.timeout"); err != nil || v != 0 {
+		t.Errorf("after setting 0: %v, %v; want 0", v, err)
+	}
+	if err := o.Set("default-branch", ""); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := o.Get("default_branch"); err != nil || v != "" {
+		t.Errorf("after setting an empty branch: %v, %v; want the empty string", v, err)
+	}
+	if err := o.Unset("credentials.timeout"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := o.Get("credentials.timeout"); err != nil || v != nil {
+		t.Errorf("after unsetting: %v, %v; want nil", v, err)
+	}
+
+	for _, set := range [][2]string{{"concurrency", "0"}, {"credentials.timeout", "-1"}, {"credentials.timeout", "soon"}, {"active_group", "x"}} {
+		if err := o.Set(set[0], set[1]); err == nil {
+			t.Errorf("setting %s to %q succeeded", set[0], set[1])
+		}
+	}
+	if o.Concurrency != nil || o.Credentials.Timeout != nil {
+		t.Errorf("rejected values were stored: %+v", o)
+	}
+}
@@ -0,0 +1,340 @@
+// Package doctor checks the environment RepoRover runs in and explains how
+// to fix what it finds.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/storage"
+	"github.com/msetsma/RepoRover/core/workspace"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Warn:
+		return "warn"
+	}
+	return "fail"
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+	// Remedy tells how to resolve a warning or failure.
+	Remedy string
+	// Details list individual findings, such as the repositories of a group
+	// with problems.
+	Details []string
+}
+
+// MinimumGitVersion is the oldest git release rr works with; it introduced
+// the version 2 porcelain status format.
+const MinimumGitVersion = "2.11"
+
+// Git checks that git is installed and recent enough.
+func Git(ctx context.Context) Result {
+	r := Result{Check: "git"}
+	version, err := git.Version(ctx)
+	if errors.Is(err, exec.ErrNotFound) {
+		r.Status, r.Message = Fail, "git was not found on PATH"
+		r.Remedy = "Install git from https://git-scm.com/downloads and make sure it is on PATH."
+		return r
+	}
+	if err != nil {
+		r.Status, r.Message = Fail, err.Error()
+		r.Remedy = "Check that running 'git version' works."
+		return r
+	}
+	if olderThan(version, MinimumGitVersion) {
+		r.Status, r.Message = Warn, fmt.Sprintf("git %s is older than %s", version, MinimumGitVersion)
+		r.Remedy = fmt.Sprintf("Upgrade git to %s or later.", MinimumGitVersion)
+		return r
+	}
+	r.Status, r.Message = Pass, "git "+version
+	return r
+}
+
+// olderThan compares the leading numeric parts of two versions such as
+// 2.39.3 and 2.11.
+func olderThan(version, minimum string) bool {
+	v, m := strings.Split(version, "."), strings.Split(minimum, ".")
+	for i := range m {
+		if i >= len(v) {
+			return true
+		}
+		a, _ := strconv.Atoi(v[i])
+		b, _ := strconv.Atoi(m[i])
+		if a != b {
+			return a < b
+		}
+	}
+	return false
+}
+
+// Config checks that the configuration file loads. A missing file is not
+// created; the defaults are returned instead. The manifest is nil when the
+// file doesn't load.
+func Config() (*config.Manifest, Result) {
+	r := Result{Check: "config"}
+	path := config.Location()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		cfg, err := config.Defaults()
+		if err != nil {
+			r.Status, r.Message = Fail, err.Error()
+			return nil, r
+		}
+		r.Status, r.Message = Warn, path+" does not exist; the defaults apply"
+		r.Remedy = "Run any other rr command to create it."
+		return cfg, r
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		r.Status, r.Message = Fail, err.Error()
+		r.Remedy = fmt.Sprintf("Fix or remove %s; removing it restores the defaults.", path)
+		return nil, r
+	}
+	r.Status, r.Message = Pass, "loaded "+path
+	return cfg, r
+}
+
+// Database checks that the database at path can be read and is at the schema
+// version of this binary, without migrating it. It reports whether the
+// database can be opened without changing it.
+func Database(path string) (bool, Result) {
+	r := Result{Check: "database"}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		r.Status, r.Message = Pass, path+" does not exist yet; it is created when first needed"
+		return false, r
+	}
+	status, err := storage.ReadSchemaStatus(path)
+	if err != nil {
+		r.Status, r.Message = Fail, err.Error()
+		r.Remedy = fmt.Sprintf("Check the permissions of %s or restore it from a backup.", path)
+		return false, r
+	}
+	switch {
+	case status.Version > storage.LatestSchemaVersion:
+		r.Status = Fail
+		r.Message = fmt.Sprintf("schema version %d is newer than this rr supports (%d)", status.Version, storage.LatestSchemaVersion)
+		r.Remedy = "Upgrade rr to the version that last migrated the database."
+		return false, r
+	case len(status.Pending()) > 0:
+		r.Status = Warn
+		r.Message = fmt.Sprintf("schema version %d, %d migrations pending", status.Version, len(status.Pending()))
+		r.Remedy = "Run 'rr db migrate'; any other command migrates it as well."
+		return false, r
+	}
+	r.Status, r.Message = Pass, fmt.Sprintf("%s at schema version %d", path, status.Version)
+	return true, r
+}
+
+// CloneDestinations checks that the clone destination and those overridden
+// per group are writable, or can be created.
+func CloneDestinations(cfg *config.Manifest) []Result {
+	destinations := map[string][]string{}
+	destinations[cfg.Paths.Groups] = nil
+	for group := range cfg.Groups {
+		dest := cfg.Resolve(group).CloneDestination
+		destinations[dest] = append(destinations[dest], group)
+	}
+
+	var results []Result
+	for _, dest := range sortedKeys(destinations) {
+		r := Result{Check: "clone destination"}
+		if groups := destinations[dest]; len(groups) > 0 {
+			sort.Strings(groups)
+			r.Check += " (" + strings.Join(groups, ", ") + ")"
+		}
+		dir, err := workspace.ExpandHome(dest)
+		if err == nil {
+			err = writable(dir)
+		}
+		if err != nil {
+			r.Status, r.Message = Fail, fmt.Sprintf("%s is not writable: %v", dest, err)
+			r.Remedy = "Fix the permissions or choose another directory with 'rr config set paths.clone_destination <dir>'."
+		} else {
+			r.Status, r.Message = Pass, dest+" is writable"
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// writable creates and removes a file in dir, or in its closest existing
+// parent when dir doesn't exist yet.
+func writable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".rr-doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Groups checks that the repositories of every group are cloned with the
+// recorded remote.
+func Groups(ctx context.Context, db *storage.Database, cfg *config.Manifest) ([]Result, error) {
+	groups, err := db.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, group := range groups {
+		r := Result{Check: "group " + group.Name}
+		repos, err := db.GroupRepositories(group.Name)
+		if err != nil {
+			return nil, err
+		}
+		settings := cfg.Resolve(group.Name)
+		ws := workspace.New(settings.CloneDestination, group.Name)
+
+		pending, broken := 0, 0
+		for _, v := range ws.Verify(ctx, repos, settings.Concurrency) {
+			switch {
+			case v.Err != nil:
+				broken++
+				r.Details = append(r.Details, fmt.Sprintf("%s: %v", v.Repo.Name, v.Err))
+			case v.Problem == workspace.ProblemPendingClone:
+				pending++
+				r.Details = append(r.Details, fmt.Sprintf("%s: %s", v.Repo.Name, v.Problem))
+			case v.Problem == workspace.ProblemRemoteMismatch:
+				broken++
+				r.Details = append(r.Details, fmt.Sprintf("%s: origin is %s, expected %s", v.Repo.Name, v.Remote, v.Repo.RemoteURL))
+			case v.Problem != workspace.ProblemNone:
+				broken++
+				r.Details = append(r.Details, fmt.Sprintf("%s: %s at %s", v.Repo.Name, v.Problem, v.Dir))
+			}
+		}
+
+		switch {
+		case broken > 0:
+			r.Status = Fail
+			r.Message = fmt.Sprintf("%d of %d repositories have problems", broken+pending, len(repos))
+			r.Remedy = fmt.Sprintf("Restore missing repositories with 'rr group clone %s', fix remotes with 'git remote set-url origin <url>' or drop repositories with 'rr group remove %s <name>'.", group.Name, group.Name)
+		case pending > 0:
+			r.Status = Warn
+			r.Message = fmt.Sprintf("%d of %d repositories are not cloned yet", pending, len(repos))
+			r.Remedy = fmt.Sprintf("Run 'rr group clone %s'.", group.Name)
+		default:
+			r.Status = Pass
+			r.Message = fmt.Sprintf("%d repositories present", len(repos))
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// integration is the configuration of one integration.
+type integration struct {
+	name     string
+	key      string
+	enabled  bool
+	url      string
+	token    string
+	required bool
+}
+
+// Integrations checks that enabled integrations, and any with a token, have
+// a token. With validate the token is also checked against the integration's
+// URL; this requires the provider packages to be imported.
+func Integrations(ctx context.Context, cfg *config.Manifest, validate bool) []Result {
+	all := []integration{
+		{"azure", "integrations.azure", cfg.Integrations.Azure.Enabled, cfg.Integrations.Azure.URL, cfg.Integrations.Azure.APIToken, true},
+		{"github", "integrations.github", cfg.Integrations.GitHub.Enabled, cfg.Integrations.GitHub.URL, cfg.Integrations.GitHub.APIToken, false},
+		{"gitlab", "integrations.gitlab", cfg.Integrations.GitLab.Enabled, cfg.Integrations.GitLab.URL, cfg.Integrations.GitLab.APIToken, false},
+	}
+
+	var results []Result
+	for _, in := range all {
+		if !in.enabled && in.token == "" {
+			continue
+		}
+		r := Result{Check: "integration " + in.name}
+		switch {
+		case in.token == "" && in.required:
+			r.Status, r.Message = Fail, "enabled without a token"
+			r.Remedy = fmt.Sprintf("Set one with 'rr config set %s.api_token <token>'.", in.key)
+		case in.token == "":
+			r.Status, r.Message = Warn, "no token; only public repositories are visible and rate limits are low"
+			r.Remedy = fmt.Sprintf("Set one with 'rr config set %s.api_token <token>'.", in.key)
+		case !validate:
+			r.Status, r.Message = Pass, "token set; run with --validate to check it"
+		default:
+			r = validateToken(ctx, cfg, in, r)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func validateToken(ctx context.Context, cfg *config.Manifest, in integration, r Result) Result {
+	url := in.url
+	if url == "" {
+		url = "the default URL"
+	}
+	provider, err := integrations.New(in.name, cfg.Integrations)
+	if err != nil {
+		r.Status, r.Message = Fail, err.Error()
+		return r
+	}
+	validator, ok := provider.(integrations.TokenValidator)
+	if !ok {
+		r.Status, r.Message = Warn, "token set; this provider cannot validate it"
+		return r
+	}
+	if err := validator.ValidateToken(ctx); err != nil {
+		r.Status, r.Message = Fail, fmt.Sprintf("token rejected by %s: %v", url, err)
+		r.Remedy = fmt.Sprintf("Create a new token and set it with 'rr config set %s.api_token <token>', or check %s.url.", in.key, in.key)
+		return r
+	}
+	r.Status, r.Message = Pass, "token accepted by "+url
+	return r
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,226 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/storage"
+)
+
+func TestOlderThan(t *testing.T) {
+	tests := []struct {
+		version, minimum string
+		want             bool
+	}{
+		{"2.39.3", "2.11", false},
+		{"2.11", "2.11", false},
+		{"2.11.0", "2.11", false},
+		{"2.10.5", "2.11", true},
+		{"1.9", "2.11", true},
+		{"3.0", "2.11", false},
+		{"2", "2.11", true},
+		// Vendor suffixes, as in 2.39.3 (Apple Git-146), don't matter.
+		{"2.39.3 (Apple Git-146)", "2.11", false},
+		{"2.9.windows.1", "2.11", true},
+	}
+	for _, tt := range tests {
+		if got := olderThan(tt.version, tt.minimum); got != tt.want {
+			t.Errorf("olderThan(%q, %q) = %t, want %t", tt.version, tt.minimum, got, tt.want)
+		}
+	}
+}
+
+func TestWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"existing", dir, false},
+		{"created later", filepath.Join(dir, "a", "b"), false},
+		{"file", file, true},
+		{"below a file", filepath.Join(file, "sub"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := writable(tt.dir); (err != nil) != tt.wantErr {
+				t.Errorf("writable = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing is left behind, missing directories are not created.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("%s holds %d entries, want only file", dir, len(entries))
+	}
+}
+
+func TestWritableReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+	if err := writable(filepath.Join(dir, "missing")); err == nil {
+		t.Error("a read-only directory is writable")
+	}
+}
+
+// setSchemaVersion opens a database at path with the latest schema and then
+// rewrites the versions recorded in it.
+func setSchemaVersion(t *testing.T, path string, statement string, args ...any) {
+	t.Helper()
+	d, err := storage.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(statement, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, path string)
+		wantStatus Status
+		wantOpen   bool
+	}{
+		{
+			name:       "missing",
+			setup:      func(t *testing.T, path string) {},
+			wantStatus: Pass,
+		},
+		{
+			name: "current",
+			setup: func(t *testing.T, path string) {
+				setSchemaVersion(t, path, `SELECT 1`)
+			},
+			wantStatus: Pass,
+			wantOpen:   true,
+		},
+		{
+			name: "pending",
+			setup: func(t *testing.T, path string) {
+				setSchemaVersion(t, path, `DELETE FROM schema_version WHERE version > 3`)
+			},
+			wantStatus: Warn,
+		},
+		{
+			name: "newer",
+			setup: func(t *testing.T, path string) {
+				setSchemaVersion(t, path, `INSERT INTO schema_version VALUES (?, 'from the future', '2030-01-01T00:00:00Z')`, storage.LatestSchemaVersion+1)
+			},
+			wantStatus: Fail,
+		},
+		{
+			name: "not a database",
+			setup: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte("not sqlite"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantStatus: Fail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rover.sqlite")
+			tt.setup(t, path)
+			open, r := Database(path)
+			if r.Status != tt.wantStatus || open != tt.wantOpen {
+				t.Errorf("Database = %t, %s: %s; want %t, %s", open, r.Status, r.Message, tt.wantOpen, tt.wantStatus)
+			}
+			if r.Status != Pass && r.Remedy == "" {
+				t.Errorf("%s without a remedy", r.Status)
+			}
+		})
+	}
+}
+
+func TestIntegrations(t *testing.T) {
+	tests := []struct {
+		name         string
+		integrations config.Integrations
+		want         map[string]Status
+	}{
+		{
+			name: "none",
+			want: map[string]Status{},
+		},
+		{
+			name: "required token missing",
+			integrations: config.Integrations{
+				Azure: config.Azure{Enabled: true},
+			},
+			want: map[string]Status{"integration azure": Fail},
+		},
+		{
+			name: "optional tokens missing",
+			integrations: config.Integrations{
+				GitHub: config.GitHub{Enabled: true},
+				GitLab: config.GitLab{Enabled: true},
+			},
+			want: map[string]Status{"integration github": Warn, "integration gitlab": Warn},
+		},
+		{
+			name: "tokens set",
+			integrations: config.Integrations{
+				Azure:  config.Azure{Enabled: true, APIToken: "a"},
+				GitHub: config.GitHub{Enabled: true, APIToken: "b"},
+			},
+			want: map[string]Status{"integration azure": Pass, "integration github": Pass},
+		},
+		{
+			// A token is checked even when the integration is disabled.
+			name: "disabled with a token",
+			integrations: config.Integrations{
+				GitLab: config.GitLab{APIToken: "c"},
+			},
+			want: map[string]Status{"integration gitlab": Pass},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Manifest{Integrations: tt.integrations}
+			got := map[string]Status{}
+			for _, r := range Integrations(context.Background(), cfg, false) {
+				got[r.Check] = r.Status
+				if r.Status != Pass && r.Remedy == "" {
+					t.Errorf("%s: %s without a remedy", r.Check, r.Status)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+			for check, status := range tt.want {
+				if got[check] != status {
+					t.Errorf("%s = %s, want %s", check, got[check], status)
+				}
+			}
+		})
+	}
+}
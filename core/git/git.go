@@ -0,0 +1,263 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Error is returned when a git invocation exits unsuccessfully.
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run executes git with the given arguments in dir and returns its stdout
+// with surrounding whitespace removed.
+func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Commands run unattended and often concurrently, so never let git
+	// block on a credential prompt.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &Error{Args: args, Stderr: stderr.String(), Err: err}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Version returns the version of the git binary, e.g. 2.43.0.
+func Version(ctx context.Context) (string, error) {
+	out, err := Run(ctx, "", "version")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(strings.TrimPrefix(out, "git version "))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unexpected output of git version: %q", out)
+	}
+	return fields[0], nil
+}
+
+// TopLevel returns the root of the working tree containing dir.
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	return Run(ctx, dir, "rev-parse", "--show-toplevel")
+}
+
+// IsBare reports whether dir is a bare repository.
+func IsBare(ctx context.Context, dir string) bool {
+	out, err := Run(ctx, dir, "rev-parse", "--is-bare-repository")
+	return err == nil && out == "true"
+}
+
+// RemoteURL returns the URL configured for the named remote. An empty string
+// and no error is returned when the remote does not exist.
+func RemoteURL(ctx context.Context, dir, remote string) (string, error) {
+	out, err := Run(ctx, dir, "remote", "get-url", remote)
+	if err != nil {
+		var gitErr *Error
+		if errors.As(err, &gitErr) && strings.Contains(gitErr.Stderr, "No such remote") {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// Credentials selects the credential helper used by commands that talk to a
+// remote. The zero value leaves the user's git configuration alone.
+type Credentials struct {
+	Helper string
+	// Timeout is how many seconds the cache helper keeps credentials.
+	Timeout int
+}
+
+// args returns the options that configure the helper. It is added to the
+// helpers of the user's git configuration, which git still consults first.
+func (c Credentials) args() []string {
+	if c.Helper == "" {
+		return nil
+	}
+	helper := c.Helper
+	if helper == "cache" && c.Timeout > 0 {
+		helper += " --timeout=" + strconv.Itoa(c.Timeout)
+	}
+	return []string{"-c", "credential.helper=" + helper}
+}
+
+// Clone clones url into dest. Both are passed after "--", so a url starting
+// with a dash is not taken for an option.
+func Clone(ctx context.Context, url, dest string, creds Credentials) error {
+	_, err := Run(ctx, "", append(creds.args(), "clone", "--quiet", "--", url, dest)...)
+	return err
+}
+
+// IsRepository reports whether dir is the root of a git working tree.
+func IsRepository(ctx context.Context, dir string) bool {
+	top, err := TopLevel(ctx, dir)
+	if err != nil {
+		return false
+	}
+	topInfo, err := os.Stat(top)
+	if err != nil {
+		return false
+	}
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return false
+	}
+	// Compare by file identity so symlinked paths such as /tmp and
+	// /private/tmp on macOS are recognised.
+	return os.SameFile(topInfo, dirInfo)
+}
+
+// RevParse resolves rev to a full commit SHA.
+func RevParse(ctx context.Context, dir, rev string) (string, error) {
+	return Run(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+}
+
+// CurrentBranch returns the checked out branch. An empty string and no error
+// is returned when HEAD is detached.
+func CurrentBranch(ctx context.Context, dir string) (string, error) {
+	out, err := Run(ctx, dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		var gitErr *Error
+		if errors.As(err, &gitErr) && gitErr.Stderr == "" {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// Upstream returns the upstream of the current branch, e.g. origin/main. An
+// empty string and no error is returned when no upstream is configured.
+func Upstream(ctx context.Context, dir string) (string, error) {
+	out, err := Run(ctx, dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		var gitErr *Error
+		if errors.As(err, &gitErr) && strings.Contains(gitErr.Stderr, "no upstream") {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// IsDirty reports whether tracked files have staged or unstaged changes.
+func IsDirty(ctx context.Context, dir string) (bool, error) {
+	out, err := Run(ctx, dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// IsAncestor reports whether commit a is an ancestor of commit b.
+func IsAncestor(ctx context.Context, dir, a, b string) (bool, error) {
+	_, err := Run(ctx, dir, "merge-base", "--is-ancestor", a, b)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Fetch fetches from the default remote of the current branch.
+func Fetch(ctx context.Context, dir string, creds Credentials) error {
+	_, err := Run(ctx, dir, append(creds.args(), "fetch", "--quiet")...)
+	return err
+}
+
+// MergeFastForward fast-forwards the current branch to rev.
+func MergeFastForward(ctx context.Context, dir, rev string) error {
+	_, err := Run(ctx, dir, "merge", "--ff-only", "--quiet", rev)
+	return err
+}
+
+// FetchAll fetches every remote and prunes deleted remote branches.
+func FetchAll(ctx context.Context, dir string, creds Credentials) error {
+	_, err := Run(ctx, dir, append(creds.args(), "fetch", "--all", "--prune", "--quiet")...)
+	return err
+}
+
+// HasRef reports whether ref, e.g. refs/heads/main, exists.
+func HasRef(ctx context.Context, dir, ref string) bool {
+	_, err := Run(ctx, dir, "show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// CheckBranchName returns an error unless name is a valid branch name.
+// Shorthands git would expand, such as @{-1}, are rejected as well.
+func CheckBranchName(ctx context.Context, name string) error {
+	out, err := Run(ctx, "", "check-ref-format", "--branch", name)
+	if err != nil || out != name {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
+
+// Checkout switches the working tree to an existing local branch. The branch
+// is never taken for a path, or for an option.
+func Checkout(ctx context.Context, dir, branch string) error {
+	if err := CheckBranchName(ctx, branch); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "checkout", "--quiet", branch, "--")
+	return err
+}
+
+// CheckoutTracking creates branch from remote/branch, sets it as upstream and
+// switches to it.
+func CheckoutTracking(ctx context.Context, dir, remote, branch string) error {
+	if err := CheckBranchName(ctx, branch); err != nil {
+		return err
+	}
+	_, err := Run(ctx, dir, "checkout", "--quiet", "--track", "-b", branch, remote+"/"+branch, "--")
+	return err
+}
+
+// ShortSHA abbreviates a commit SHA for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// ListFiles returns the paths of the files tracked in the working tree at dir,
+// relative to dir and separated by forward slashes.
+func ListFiles(ctx context.Context, dir string) ([]string, error) {
+	out, err := Run(ctx, dir, "ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimRight(out, "\x00"), "\x00"), nil
+}
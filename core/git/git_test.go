@@ -0,0 +1,224 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// run runs git in dir and fails the test on errors.
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := Run(context.Background(), dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// commit commits a change to file in the working tree dir.
+func commit(t *testing.T, dir, file, message string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(dir, file), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString(message + "\n")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(t, dir, "add", file)
+	run(t, dir, "commit", "--quiet", "-m", message)
+}
+
+// bareRepository creates a bare repository with one commit on main and
+// returns its path. Git runs without the user's configuration.
+func bareRepository(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ada")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ada@example.com")
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "origin.git")
+	run(t, root, "init", "--quiet", "--bare", bare)
+	run(t, bare, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	seed := filepath.Join(root, "seed")
+	run(t, root, "init", "--quiet", seed)
+	run(t, seed, "symbolic-ref", "HEAD", "refs/heads/main")
+	commit(t, seed, "README", "initial")
+	run(t, seed, "push", "--quiet", bare, "main")
+	return bare
+}
+
+func TestClone(t *testing.T) {
+	ctx := context.Background()
+	bare := bareRepository(t)
+	dest := filepath.Join(t.TempDir(), "clone")
+
+	if err := Clone(ctx, bare, dest, Credentials{}); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if !IsRepository(ctx, dest) {
+		t.Fatalf("%s is not a repository", dest)
+	}
+	if remote, err := RemoteURL(ctx, dest, "origin"); err != nil || remote != bare {
+		t.Errorf("origin = %q, %v; want %s", remote, err, bare)
+	}
+	if branch, err := CurrentBranch(ctx, dest); err != nil || branch != "main" {
+		t.Errorf("branch = %q, %v; want main", branch, err)
+	}
+}
+
+func TestCloneDashURL(t *testing.T) {
+	bare := bareRepository(t)
+	dir := t.TempDir()
+	// Taken for an option, the url would make git clone bare into the
+	// working directory and run the command as its upload-pack.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	marker := filepath.Join(dir, "marker")
+	if err := Clone(context.Background(), "--upload-pack=touch "+marker, bare, Credentials{}); err == nil {
+		t.Fatal("Clone succeeded, want an error")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("the url was taken for an option")
+	}
+}
+
+func TestFetchAndFastForward(t *testing.T) {
+	ctx := context.Background()
+	bare := bareRepository(t)
+	root := t.TempDir()
+	local, other := filepath.Join(root, "local"), filepath.Join(root, "other")
+	for _, dir := range []string{local, other} {
+		if err := Clone(ctx, bare, dir, Credentials{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit(t, other, "README", "upstream change")
+	run(t, other, "push", "--quiet")
+	want := run(t, other, "rev-parse", "HEAD")
+
+	if err := Fetch(ctx, local, Credentials{}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	upstream, err := Upstream(ctx, local)
+	if err != nil || upstream != "origin/main" {
+		t.Fatalf("upstream = %q, %v; want origin/main", upstream, err)
+	}
+	if err := MergeFastForward(ctx, local, upstream); err != nil {
+		t.Fatalf("MergeFastForward: %v", err)
+	}
+	if got, _ := RevParse(ctx, local, "HEAD"); got != want {
+		t.Errorf("HEAD = %s, want %s", got, want)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	ctx := context.Background()
+	bare := bareRepository(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	if err := Clone(ctx, bare, dir, Credentials{}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := GetStatus(ctx, dir)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if status.Branch != "main" || status.Upstream != "origin/main" || status.Dirty() {
+		t.Errorf("clean clone status = %+v", status)
+	}
+
+	commit(t, dir, "README", "local change")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	status, err = GetStatus(ctx, dir)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	want := Status{Branch: "main", Upstream: "origin/main", Ahead: 1, Unstaged: 1, Untracked: 1}
+	if *status != want {
+		t.Errorf("status = %+v, want %+v", *status, want)
+	}
+}
+
+func TestCheckBranchName(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{"main", "feature/login", "release-1.2"} {
+		if err := CheckBranchName(ctx, name); err != nil {
+			t.Errorf("CheckBranchName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-f", "--orphan=x", "a..b", "a b", "HEAD", "@{-1}", "feature/"} {
+		if err := CheckBranchName(ctx, name); err == nil {
+			t.Errorf("CheckBranchName(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	ctx := context.Background()
+	bare := bareRepository(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	if err := Clone(ctx, bare, dir, Credentials{}); err != nil {
+		t.Fatal(err)
+	}
+	run(t, dir, "branch", "feature")
+	run(t, dir, "push", "--quiet", "origin", "feature:release")
+
+	if err := Checkout(ctx, dir, "feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if branch, _ := CurrentBranch(ctx, dir); branch != "feature" {
+		t.Errorf("branch = %q, want feature", branch)
+	}
+	if err := Fetch(ctx, dir, Credentials{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckoutTracking(ctx, dir, "origin", "release"); err != nil {
+		t.Fatalf("CheckoutTracking: %v", err)
+	}
+	if upstream, err := Upstream(ctx, dir); err != nil || upstream != "origin/release" {
+		t.Errorf("upstream = %q, %v; want origin/release", upstream, err)
+	}
+
+	// A file named like a missing branch is not checked out instead,
+	// discarding its changes.
+	readme := filepath.Join(dir, "README")
+	if err := os.WriteFile(readme, []byte("local change\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Checkout(ctx, dir, "README"); err == nil {
+		t.Error("checking out the README branch succeeded")
+	}
+	if data, _ := os.ReadFile(readme); string(data) != "local change\n" {
+		t.Errorf("README = %q, the local change was discarded", data)
+	}
+	for _, branch := range []string{"-f", "--detach"} {
+		if err := Checkout(ctx, dir, branch); err == nil {
+			t.Errorf("checking out %q succeeded", branch)
+		}
+		if err := CheckoutTracking(ctx, dir, "origin", branch); err == nil {
+			t.Errorf("creating %q succeeded", branch)
+		}
+	}
+}
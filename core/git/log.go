@@ -0,0 +1,109 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit as read from git log.
+type Commit struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	Date        time.Time
+	Subject     string
+	Files       []FileStat
+}
+
+// FileStat is the number of lines a commit changed in a file. Binary files
+// are listed with zero insertions and deletions.
+type FileStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+}
+
+// Insertions returns the lines added by the commit.
+func (c Commit) Insertions() int {
+	n := 0
+	for _, f := range c.Files {
+		n += f.Insertions
+	}
+	return n
+}
+
+// Deletions returns the lines removed by the commit.
+func (c Commit) Deletions() int {
+	n := 0
+	for _, f := range c.Files {
+		n += f.Deletions
+	}
+	return n
+}
+
+// logFormat separates commits with RS and header fields with US so subjects
+// can contain anything but a newline. With -z the header and every file of
+// --numstat end in NUL, and paths are printed verbatim instead of quoted.
+const logFormat = "--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%s"
+
+// Log returns the commits reachable from rev, newest first. When since is not
+// empty, commits reachable from since are left out.
+func Log(ctx context.Context, dir, rev, since string) ([]Commit, error) {
+	rng := rev
+	if since != "" {
+		rng = since + ".." + rev
+	}
+	out, err := Run(ctx, dir, "log", "-z", logFormat, "--numstat", "--no-renames", rng, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(out)
+}
+
+func parseLog(out string) ([]Commit, error) {
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		entries := strings.Split(record, "\x00")
+		fields := strings.Split(entries[0], "\x1f")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git log output: %q", entries[0])
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date %q: %w", fields[3], err)
+		}
+		commit := Commit{SHA: fields[0], Author: fields[1], AuthorEmail: fields[2], Date: date, Subject: fields[4]}
+
+		for _, entry := range entries[1:] {
+			// The paths themselves may contain tabs.
+			parts := strings.SplitN(strings.TrimPrefix(entry, "\n"), "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			// Binary files report "-" for both counts.
+			insertions, _ := strconv.Atoi(parts[0])
+			deletions, _ := strconv.Atoi(parts[1])
+			commit.Files = append(commit.Files, FileStat{Path: parts[2], Insertions: insertions, Deletions: deletions})
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// LocalBranches returns the names of the local branches.
+func LocalBranches(ctx context.Context, dir string) ([]string, error) {
+	out, err := Run(ctx, dir, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	header := func(sha, subject string) string {
+		return "\x1e" + sha + "\x1fAda\x1fada@example.com\x1f2024-03-01T10:00:00+01:00\x1f" + subject + "\x00"
+	}
+	out := header("b2", "only files with odd names") + "\n" +
+		"-\t-\timage.png\x00" +
+		"2\t0\ttab\tname.txt\x00" +
+		"1\t1\tü \"quoted\".txt\x00" +
+		"3\t1\tnew\nline\x00" +
+		header("a1", "empty: no files") + "\n"
+
+	commits, err := parseLog(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("got %d commits, want 2: %+v", len(commits), commits)
+	}
+
+	c := commits[0]
+	date := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	if c.SHA != "b2" || c.Author != "Ada" || c.AuthorEmail != "ada@example.com" || !c.Date.Equal(date) || c.Subject != "only files with odd names" {
+		t.Errorf("header = %+v", c)
+	}
+	want := []FileStat{
+		{Path: "image.png"},
+		{Path: "tab\tname.txt", Insertions: 2},
+		{Path: "ü \"quoted\".txt", Insertions: 1, Deletions: 1},
+		{Path: "new\nline", Insertions: 3, Deletions: 1},
+	}
+	if !reflect.DeepEqual(c.Files, want) {
+		t.Errorf("files = %q, want %q", c.Files, want)
+	}
+	if c.Insertions() != 6 || c.Deletions() != 2 {
+		t.Errorf("insertions, deletions = %d, %d; want 6, 2", c.Insertions(), c.Deletions())
+	}
+
+	if commits[1].SHA != "a1" || len(commits[1].Files) != 0 {
+		t.Errorf("commit without files = %+v", commits[1])
+	}
+}
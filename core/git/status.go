@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Status summarises the state of a working tree.
+type Status struct {
+	Branch    string
+	Upstream  string
+	Ahead     int
+	Behind    int
+	Staged    int
+	Unstaged  int
+	Untracked int
+	Stashes   int
+}
+
+// Dirty reports whether the working tree has any local changes.
+func (s *Status) Dirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked > 0
+}
+
+// GetStatus reads the status of the working tree in dir.
+func GetStatus(ctx context.Context, dir string) (*Status, error) {
+	out, err := Run(ctx, dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "#":
+			parseBranchHeader(status, fields[1:])
+		case "1", "2":
+			if len(fields) < 2 || len(fields[1]) != 2 {
+				continue
+			}
+			if fields[1][0] != '.' {
+				status.Staged++
+			}
+			if fields[1][1] != '.' {
+				status.Unstaged++
+			}
+		case "u":
+			status.Unstaged++
+		case "?":
+			status.Untracked++
+		}
+	}
+
+	stashes, err := Run(ctx, dir, "stash", "list")
+	if err != nil {
+		return nil, err
+	}
+	if stashes != "" {
+		status.Stashes = len(strings.Split(stashes, "\n"))
+	}
+	return status, nil
+}
+
+func parseBranchHeader(status *Status, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	switch fields[0] {
+	case "branch.head":
+		if fields[1] != "(detached)" {
+			status.Branch = fields[1]
+		}
+	case "branch.upstream":
+		status.Upstream = fields[1]
+	case "branch.ab":
+		if len(fields) == 3 {
+			fmt.Sscanf(fields[1], "+%d", &status.Ahead)
+			fmt.Sscanf(fields[2], "-%d", &status.Behind)
+		}
+	}
+}
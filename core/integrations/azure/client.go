@@ -0,0 +1,74 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/msetsma/RepoRover/core/integrations"
+)
+
+// DefaultURL is the base URL of Azure DevOps Services.
+const DefaultURL = "https://dev.azure.com"
+
+const apiVersion = "7.1"
+
+// continuationHeader carries the token for the next page of a list response.
+const continuationHeader = "x-ms-continuationtoken"
+
+// Client talks to the Azure DevOps REST API. BaseURL is the organization
+// host, DefaultURL for Azure DevOps Services, and Token a personal access
+// token.
+type Client struct {
+	integrations.Client
+}
+
+// NewClient returns a client for the Azure DevOps instance at baseURL. An
+// empty baseURL means DefaultURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	c := &Client{integrations.NewClient("azure devops", baseURL, token)}
+	c.Authenticate = func(req *http.Request, token string) {
+		req.SetBasicAuth("", token)
+	}
+	c.Header.Set("Accept", "application/json")
+	return c
+}
+
+// get requests path below the base URL and decodes the JSON response into v.
+// It returns the continuation token of the response, if any.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) (string, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", apiVersion)
+	header, err := c.Get(ctx, c.BaseURL+path+"?"+query.Encode(), v)
+	if err != nil {
+		return "", err
+	}
+	return header.Get(continuationHeader), nil
+}
+
+// list follows continuation tokens from path and returns every item.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	var items []T
+	for {
+		var page struct {
+			Value []T `json:"value"`
+		}
+		token, err := c.get(ctx, path, query, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Value...)
+		if token == "" {
+			return items, nil
+		}
+		query.Set("continuationToken", token)
+	}
+}
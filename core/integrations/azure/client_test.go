@@ -0,0 +1,69 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientRepositories(t *testing.T) {
+	// pages maps the continuation token of a request to the token of the next
+	// page and the repositories of this one.
+	pages := map[string]struct {
+		next  string
+		names []string
+	}{
+		"":     {"next", []string{"api", "web"}},
+		"next": {"", []string{"old"}},
+	}
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/acme/platform/_apis/git/repositories" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if _, password, _ := r.BasicAuth(); password != "secret" {
+			t.Errorf("basic auth password = %q, want the token", password)
+		}
+		token := r.URL.Query().Get("continuationToken")
+		tokens = append(tokens, token)
+		page, ok := pages[token]
+		if !ok {
+			t.Errorf("unexpected continuation token %q", token)
+			http.NotFound(w, r)
+			return
+		}
+		if page.next != "" {
+			w.Header().Set(continuationHeader, page.next)
+		}
+		fmt.Fprint(w, `{"value": [`)
+		for i, name := range page.names {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id": %q, "name": %q, "isDisabled": %t}`, name, name, name == "old")
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	defer srv.Close()
+
+	repos, err := NewClient(srv.URL, "secret").Repositories(context.Background(), "acme", "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+		if repo.Disabled != (repo.Name == "old") {
+			t.Errorf("%s: disabled = %t", repo.Name, repo.Disabled)
+		}
+	}
+	if want := []string{"api", "web", "old"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("repositories = %v, want %v", names, want)
+	}
+	if want := []string{"", "next"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("continuation tokens = %q, want %q", tokens, want)
+	}
+}
@@ -0,0 +1,45 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// Project is an Azure DevOps project.
+type Project struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// Projects retrieves every project of an organization.
+func (c *Client) Projects(ctx context.Context, org string) ([]Project, error) {
+	query := url.Values{}
+	query.Set("$top", "100")
+	return list[Project](ctx, c, "/"+url.PathEscape(org)+"/_apis/projects", query)
+}
+
+// OrganizationRepositories retrieves the repositories of every project of an
+// organization. Projects that are still being created or deleted are skipped.
+func (c *Client) OrganizationRepositories(ctx context.Context, org string) ([]models.Repository, error) {
+	projects, err := c.Projects(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []models.Repository
+	for _, project := range projects {
+		if project.State != "" && project.State != "wellFormed" {
+			continue
+		}
+		projectRepos, err := c.Repositories(ctx, org, project.Name)
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", project.Name, err)
+		}
+		repos = append(repos, projectRepos...)
+	}
+	return repos, nil
+}
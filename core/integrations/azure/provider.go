@@ -0,0 +1,56 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+func init() {
+	integrations.Register("azure", func(cfg config.Integrations) (integrations.Provider, error) {
+		if cfg.Azure.APIToken == "" {
+			return nil, fmt.Errorf("no Azure DevOps token configured; set integrations.azure.api_token")
+		}
+		return NewClient(cfg.Azure.URL, cfg.Azure.APIToken), nil
+	})
+}
+
+// Discover lists the repositories of scope.Project, or of every project of
+// scope.Org when scope.AllProjects is set.
+func (c *Client) Discover(ctx context.Context, scope integrations.Scope) ([]models.Repository, error) {
+	if scope.Org == "" {
+		return nil, fmt.Errorf("%w: azure requires an organization", integrations.ErrInvalidScope)
+	}
+	if (scope.Project == "") == !scope.AllProjects {
+		return nil, fmt.Errorf("%w: azure requires either a project or all projects", integrations.ErrInvalidScope)
+	}
+	if scope.User != "" || scope.Topic != "" {
+		return nil, fmt.Errorf("%w: azure does not support users or topics", integrations.ErrInvalidScope)
+	}
+
+	if scope.AllProjects {
+		return c.OrganizationRepositories(ctx, scope.Org)
+	}
+	return c.Repositories(ctx, scope.Org, scope.Project)
+}
+
+// ProfileURL is the Azure DevOps Services endpoint that describes the owner
+// of a token. Azure DevOps Server is checked at its own address instead.
+const ProfileURL = "https://app.vssps.visualstudio.com"
+
+// ValidateToken asks Azure DevOps Services for the profile of the token's
+// owner, or Azure DevOps Server for its connection data.
+func (c *Client) ValidateToken(ctx context.Context) error {
+	var v struct{}
+	if c.BaseURL == DefaultURL {
+		profile := *c
+		profile.BaseURL = ProfileURL
+		_, err := profile.get(ctx, "/_apis/profile/profiles/me", nil, &v)
+		return err
+	}
+	_, err := c.get(ctx, "/_apis/connectionData", nil, &v)
+	return err
+}
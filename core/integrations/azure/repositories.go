@@ -0,0 +1,65 @@
+package azure
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// gitRepository is a repository as returned by the Azure DevOps API.
+type gitRepository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	DefaultBranch string  `json:"defaultBranch"`
+	RemoteURL     string  `json:"remoteUrl"`
+	SSHURL        string  `json:"sshUrl"`
+	Project       Project `json:"project"`
+	IsDisabled    bool    `json:"isDisabled"`
+	IsFork        bool    `json:"isFork"`
+}
+
+func (r gitRepository) model() models.Repository {
+	return models.Repository{
+		Provider:      "azure",
+		ProviderID:    r.ID,
+		Name:          r.Name,
+		Project:       r.Project.Name,
+		DefaultBranch: strings.TrimPrefix(r.DefaultBranch, "refs/heads/"),
+		RemoteURL:     r.RemoteURL,
+		SSHURL:        r.SSHURL,
+		Fork:          r.IsFork,
+		Disabled:      r.IsDisabled,
+		LastUpdated:   time.Now(),
+	}
+}
+
+func repositoriesPath(org, project string) string {
+	return "/" + url.PathEscape(org) + "/" + url.PathEscape(project) + "/_apis/git/repositories"
+}
+
+// Repository fetches detailed information about a repository
+func (c *Client) Repository(ctx context.Context, org, project, repoID string) (*models.Repository, error) {
+	var repo gitRepository
+	if _, err := c.get(ctx, repositoriesPath(org, project)+"/"+url.PathEscape(repoID), nil, &repo); err != nil {
+		return nil, err
+	}
+	model := repo.model()
+	return &model, nil
+}
+
+// Repositories retrieves the repositories of a project, including disabled
+// ones.
+func (c *Client) Repositories(ctx context.Context, org, project string) ([]models.Repository, error) {
+	repos, err := list[gitRepository](ctx, c, repositoriesPath(org, project), nil)
+	if err != nil {
+		return nil, err
+	}
+	discovered := make([]models.Repository, 0, len(repos))
+	for _, repo := range repos {
+		discovered = append(discovered, repo.model())
+	}
+	return discovered, nil
+}
@@ -0,0 +1,201 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRetryWait is the longest a client waits before retrying a request, e.g.
+// for an exhausted rate limit to reset, before giving up.
+const MaxRetryWait = time.Minute
+
+// Client performs the requests of the provider clients. It authenticates
+// them, retries rate limited and failed ones and turns error responses into
+// APIErrors; the providers build the URLs and follow their pages.
+type Client struct {
+	// HTTPClient performs the requests. It defaults to a client with a timeout.
+	HTTPClient *http.Client
+	// BaseURL is the address of the service the provider builds URLs on.
+	BaseURL string
+	// Token is the access token Authenticate adds to requests.
+	Token string
+	// MaxRetries is how often a request is retried after a rate limit or 5xx
+	// response.
+	MaxRetries int
+	// Backoff is the wait before the first retry; it doubles for every
+	// following one unless the server says how long to wait.
+	Backoff time.Duration
+
+	// Service names the service in errors, e.g. github.
+	Service string
+	// Authenticate adds Token to a request. It is not called without a token.
+	Authenticate func(req *http.Request, token string)
+	// Header is sent with every request.
+	Header http.Header
+}
+
+// NewClient returns a client for service at baseURL with the default timeout
+// and retries.
+func NewClient(service, baseURL, token string) Client {
+	return Client{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		MaxRetries: 4,
+		Backoff:    time.Second,
+		Service:    service,
+		Header:     http.Header{},
+	}
+}
+
+// APIError is returned for responses other than 200 OK.
+type APIError struct {
+	Service    string
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s: %s", e.Service, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Service, e.Status)
+}
+
+// Unwrap returns ErrUnauthorized when the token was rejected.
+func (e *APIError) Unwrap() error {
+	if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusNonAuthoritativeInfo {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// Get requests endpoint and decodes the JSON response into v. It returns the
+// header of the response, which tells where the next page is. Requests that
+// fail to reach the service, such as after a connection reset or a timeout,
+// are retried like rate limited ones.
+func (c *Client) Get(ctx context.Context, endpoint string, v any) (http.Header, error) {
+	req, err := c.newRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	wait := c.Backoff
+	for attempt := 0; ; attempt++ {
+		delay := wait
+		resp, err := client.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || attempt >= c.MaxRetries {
+				return nil, err
+			}
+		case resp.StatusCode == http.StatusOK:
+			defer resp.Body.Close()
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				return nil, fmt.Errorf("%s: failed to decode response: %w", c.Service, err)
+			}
+			return resp.Header, nil
+		default:
+			apiErr := c.newAPIError(resp)
+			var retry bool
+			delay, retry = retryDelay(resp, wait)
+			if !retry || attempt >= c.MaxRetries {
+				return nil, apiErr
+			}
+			if delay > MaxRetryWait {
+				return nil, fmt.Errorf("%w; the rate limit resets in %s", apiErr, delay.Round(time.Second))
+			}
+		}
+		if err := Sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		wait *= 2
+	}
+}
+
+// retryDelay decides whether a failed response is worth retrying and how long
+// to wait first. Only rate limited requests and server errors are retried;
+// other errors, such as a rejected token, would fail again. Rate limited
+// requests are answered with 429, or with 403 by GitHub, and say when to retry
+// through Retry-After or X-RateLimit-Reset.
+func retryDelay(resp *http.Response, backoff time.Duration) (time.Duration, bool) {
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0")
+	if !limited && resp.StatusCode < 500 {
+		return 0, false
+	}
+	if d, ok := RetryAfter(resp.Header.Get("Retry-After")); ok {
+		return d, true
+	}
+	if limited {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0), true
+		}
+	}
+	return backoff, true
+}
+
+// newRequest builds the GET request for endpoint. It has no body, so it is
+// sent again as it is when retried.
+func (c *Client) newRequest(ctx context.Context, endpoint string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.Header {
+		req.Header[key] = values
+	}
+	if c.Token != "" && c.Authenticate != nil {
+		c.Authenticate(req, c.Token)
+	}
+	return req, nil
+}
+
+// newAPIError consumes the body of a failed response. Services report errors
+// as {"message": ...} or, GitLab for OAuth errors, {"error": ...}.
+func (c *Client) newAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	apiErr := &APIError{Service: c.Service, StatusCode: resp.StatusCode, Status: resp.Status}
+	// Azure DevOps answers an invalid token with 203 and a sign-in page.
+	if resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		apiErr.Message = "authentication failed; check the personal access token"
+		return apiErr
+	}
+	var body struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil {
+		switch {
+		case body.Message != nil:
+			apiErr.Message = fmt.Sprint(body.Message)
+		case body.Error != "":
+			apiErr.Message = body.Error
+		}
+	}
+	return apiErr
+}
+
+// NextLink returns the rel="next" URL of a Link header.
+func NextLink(header http.Header) string {
+	for _, part := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+	return ""
+}
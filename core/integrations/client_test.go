@@ -0,0 +1,213 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientGet(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "api"}`)
+	}
+	status := func(code int, header ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i+1 < len(header); i += 2 {
+				w.Header().Set(header[i], header[i+1])
+			}
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"message": "nope"}`)
+		}
+	}
+	// reset drops the connection without answering.
+	reset := func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	}
+	past := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		responses []http.HandlerFunc
+		// wantStatus is the status of the APIError returned, if any.
+		wantStatus int
+		wantErr    error
+	}{
+		{
+			name:      "200",
+			responses: []http.HandlerFunc{ok},
+		},
+		{
+			name:      "429 with Retry-After",
+			responses: []http.HandlerFunc{status(http.StatusTooManyRequests, "Retry-After", "0"), ok},
+		},
+		{
+			name:      "503 with Retry-After",
+			responses: []http.HandlerFunc{status(http.StatusServiceUnavailable, "Retry-After", "0"), ok},
+		},
+		{
+			name:      "500",
+			responses: []http.HandlerFunc{status(http.StatusInternalServerError), ok},
+		},
+		{
+			name:      "connection reset",
+			responses: []http.HandlerFunc{reset, ok},
+		},
+		{
+			name: "403 rate limit",
+			responses: []http.HandlerFunc{
+				status(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", past),
+				ok,
+			},
+		},
+		{
+			name:       "rate limit resetting too late",
+			responses:  []http.HandlerFunc{status(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", future)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "403",
+			responses:  []http.HandlerFunc{status(http.StatusForbidden)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "401 with Retry-After",
+			responses:  []http.HandlerFunc{status(http.StatusUnauthorized, "Retry-After", "0")},
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    ErrUnauthorized,
+		},
+		{
+			name:       "404 with Retry-After",
+			responses:  []http.HandlerFunc{status(http.StatusNotFound, "Retry-After", "0")},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "203 sign-in page",
+			responses:  []http.HandlerFunc{status(http.StatusNonAuthoritativeInfo)},
+			wantStatus: http.StatusNonAuthoritativeInfo,
+			wantErr:    ErrUnauthorized,
+		},
+		{
+			name: "retries exhausted",
+			responses: []http.HandlerFunc{
+				status(http.StatusBadGateway), status(http.StatusBadGateway), status(http.StatusBadGateway),
+			},
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if got := r.Header.Get("Accept"); got != "application/json" {
+					t.Errorf("Accept = %q", got)
+				}
+				requests++
+				if requests > len(tt.responses) {
+					t.Errorf("unexpected request %d", requests)
+					http.NotFound(w, r)
+					return
+				}
+				tt.responses[requests-1](w, r)
+			}))
+			defer srv.Close()
+
+			c := NewClient("test", srv.URL, "secret")
+			c.MaxRetries = 2
+			c.Backoff = time.Millisecond
+			c.Authenticate = func(req *http.Request, token string) {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			c.Header.Set("Accept", "application/json")
+
+			var v struct{ Name string }
+			_, err := c.Get(context.Background(), c.BaseURL+"/repos", &v)
+			var apiErr *APIError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("err = %v", err)
+			case tt.wantStatus == 0 && v.Name != "api":
+				t.Errorf("decoded %+v", v)
+			case tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus):
+				t.Errorf("err = %v, want an APIError with status %d", err, tt.wantStatus)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if requests != len(tt.responses) {
+				t.Errorf("requests = %d, want %d", requests, len(tt.responses))
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	const backoff = 3 * time.Second
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{"429", http.StatusTooManyRequests, nil, backoff, true},
+		{"429 with Retry-After", http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, 7 * time.Second, true},
+		{"503 with Retry-After", http.StatusServiceUnavailable, http.Header{"Retry-After": {"7"}}, 7 * time.Second, true},
+		{"500", http.StatusInternalServerError, nil, backoff, true},
+		{"403 rate limit", http.StatusForbidden, http.Header{"X-Ratelimit-Remaining": {"0"}, "Retry-After": {"7"}}, 7 * time.Second, true},
+		{"403 rate limit reset", http.StatusForbidden, http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {reset}}, time.Hour, true},
+		{"403", http.StatusForbidden, http.Header{"X-Ratelimit-Remaining": {"12"}}, 0, false},
+		{"400 with Retry-After", http.StatusBadRequest, http.Header{"Retry-After": {"7"}}, 0, false},
+		{"401 with Retry-After", http.StatusUnauthorized, http.Header{"Retry-After": {"7"}}, 0, false},
+		{"404 with Retry-After", http.StatusNotFound, http.Header{"Retry-After": {"7"}}, 0, false},
+		{"409 with Retry-After", http.StatusConflict, http.Header{"Retry-After": {"7"}}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: tt.header}
+			if resp.Header == nil {
+				resp.Header = http.Header{}
+			}
+			delay, retry := retryDelay(resp, backoff)
+			if retry != tt.wantRetry {
+				t.Errorf("retry = %t, want %t", retry, tt.wantRetry)
+			}
+			// A reset time is only accurate to the second.
+			if diff := delay - tt.wantDelay; diff < -time.Second || diff > time.Second {
+				t.Errorf("delay = %s, want %s", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`<https://api.example.com/repos?page=2>; rel="next"`, "https://api.example.com/repos?page=2"},
+		{`<https://api.example.com/repos?page=1>; rel="prev", <https://api.example.com/repos?page=3>; rel="next", <https://api.example.com/repos?page=9>; rel="last"`, "https://api.example.com/repos?page=3"},
+		{`<https://api.example.com/repos?page=9>; rel="last"`, ""},
+	}
+	for _, tt := range tests {
+		header := http.Header{"Link": {tt.link}}
+		if got := NextLink(header); got != tt.want {
+			t.Errorf("NextLink(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
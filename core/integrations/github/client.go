@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/msetsma/RepoRover/core/integrations"
+)
+
+// DefaultURL is the API endpoint of github.com.
+const DefaultURL = "https://api.github.com"
+
+// Client talks to the GitHub REST API. BaseURL is the API endpoint,
+// DefaultURL for github.com. Without a Token only public repositories are
+// visible and rate limits are low.
+type Client struct {
+	integrations.Client
+}
+
+// NewClient returns a client for the GitHub API at baseURL. An empty baseURL
+// means DefaultURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	c := &Client{integrations.NewClient("github", baseURL, token)}
+	c.Authenticate = func(req *http.Request, token string) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	c.Header.Set("Accept", "application/vnd.github+json")
+	c.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return c
+}
+
+// get requests endpoint and decodes the JSON response into v. It returns the
+// URL of the next page, if any.
+func (c *Client) get(ctx context.Context, endpoint string, v any) (string, error) {
+	header, err := c.Get(ctx, endpoint, v)
+	if err != nil {
+		return "", err
+	}
+	return integrations.NextLink(header), nil
+}
+
+// endpoint builds the URL of path below the base URL.
+func (c *Client) endpoint(path string, query url.Values) string {
+	if len(query) == 0 {
+		return c.BaseURL + path
+	}
+	return c.BaseURL + path + "?" + query.Encode()
+}
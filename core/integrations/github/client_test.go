@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientOrgRepositories(t *testing.T) {
+	// pages maps the page parameter of a request to the next page and the
+	// repositories of this one.
+	pages := map[string]struct {
+		next  string
+		names []string
+	}{
+		"":  {"2", []string{"api", "web"}},
+		"2": {"", []string{"cli"}},
+	}
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/acme/repos" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		number := r.URL.Query().Get("page")
+		requested = append(requested, number)
+		page, ok := pages[number]
+		if !ok {
+			t.Errorf("unexpected page %q", number)
+			http.NotFound(w, r)
+			return
+		}
+		if page.next != "" {
+			link := fmt.Sprintf(`<http://%s/orgs/acme/repos?page=%s>; rel="next", <http://%s/orgs/acme/repos?page=9>; rel="last"`, r.Host, page.next, r.Host)
+			w.Header().Set("Link", link)
+		}
+		var repos []string
+		for _, name := range page.names {
+			repos = append(repos, fmt.Sprintf(`{"name": %q, "owner": {"login": "acme"}}`, name))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(repos, ","))
+	}))
+	defer srv.Close()
+
+	repos, err := NewClient(srv.URL, "secret").OrgRepositories(context.Background(), "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	if want := []string{"api", "web", "cli"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("repositories = %v, want %v", names, want)
+	}
+	if want := []string{"", "2"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("pages = %q, want %q", requested, want)
+	}
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+func init() {
+	integrations.Register("github", func(cfg config.Integrations) (integrations.Provider, error) {
+		return NewClient(cfg.GitHub.URL, cfg.GitHub.APIToken), nil
+	})
+}
+
+// Discover lists the repositories of scope.Org or scope.User, narrowed down to
+// those tagged with scope.Topic when set. With only a topic every repository
+// with that topic is searched.
+func (c *Client) Discover(ctx context.Context, scope integrations.Scope) ([]models.Repository, error) {
+	if scope.Project != "" || scope.AllProjects {
+		return nil, fmt.Errorf("%w: github has no projects; use an organization or user", integrations.ErrInvalidScope)
+	}
+	if scope.Org != "" && scope.User != "" {
+		return nil, fmt.Errorf("%w: github takes an organization or a user, not both", integrations.ErrInvalidScope)
+	}
+
+	switch {
+	case scope.Topic != "":
+		return c.SearchRepositories(ctx, TopicQuery(scope.Topic, scope.Org, scope.User))
+	case scope.Org != "":
+		return c.OrgRepositories(ctx, scope.Org)
+	case scope.User != "":
+		return c.UserRepositories(ctx, scope.User)
+	}
+	return nil, fmt.Errorf("%w: github requires an organization, user or topic", integrations.ErrInvalidScope)
+}
+
+// ValidateToken requests the authenticated user.
+func (c *Client) ValidateToken(ctx context.Context) error {
+	var user struct{}
+	_, err := c.get(ctx, c.endpoint("/user", nil), &user)
+	return err
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// repository is a repository as returned by the GitHub API.
+type repository struct {
+	ID            int64    `json:"id"`
+	Name          string   `json:"name"`
+	FullName      string   `json:"full_name"`
+	Owner         owner    `json:"owner"`
+	CloneURL      string   `json:"clone_url"`
+	SSHURL        string   `json:"ssh_url"`
+	DefaultBranch string   `json:"default_branch"`
+	Archived      bool     `json:"archived"`
+	Fork          bool     `json:"fork"`
+	Disabled      bool     `json:"disabled"`
+	Topics        []string `json:"topics"`
+}
+
+// owner is the user or organization owning a repository.
+type owner struct {
+	Login string `json:"login"`
+}
+
+func (r repository) model() models.Repository {
+	return models.Repository{
+		Provider:      "github",
+		ProviderID:    strconv.FormatInt(r.ID, 10),
+		Name:          r.Name,
+		Project:       r.Owner.Login,
+		DefaultBranch: r.DefaultBranch,
+		RemoteURL:     r.CloneURL,
+		SSHURL:        r.SSHURL,
+		Archived:      r.Archived,
+		Fork:          r.Fork,
+		Disabled:      r.Disabled,
+		LastUpdated:   time.Now(),
+	}
+}
+
+func toModels(repos []repository) []models.Repository {
+	discovered := make([]models.Repository, 0, len(repos))
+	for _, repo := range repos {
+		discovered = append(discovered, repo.model())
+	}
+	return discovered
+}
+
+// OrgRepositories retrieves every repository of an organization visible to
+// the token.
+func (c *Client) OrgRepositories(ctx context.Context, org string) ([]models.Repository, error) {
+	query := url.Values{"type": {"all"}, "per_page": {"100"}}
+	repos, err := listPages[repository](ctx, c, c.endpoint("/orgs/"+url.PathEscape(org)+"/repos", query))
+	if err != nil {
+		return nil, err
+	}
+	return toModels(repos), nil
+}
+
+// UserRepositories retrieves the repositories owned by a user.
+func (c *Client) UserRepositories(ctx context.Context, user string) ([]models.Repository, error) {
+	query := url.Values{"type": {"owner"}, "per_page": {"100"}}
+	repos, err := listPages[repository](ctx, c, c.endpoint("/users/"+url.PathEscape(user)+"/repos", query))
+	if err != nil {
+		return nil, err
+	}
+	return toModels(repos), nil
+}
+
+// SearchRepositories retrieves the repositories matching a search query such
+// as "topic:go org:acme". The search API returns at most 1000 results.
+func (c *Client) SearchRepositories(ctx context.Context, q string) ([]models.Repository, error) {
+	query := url.Values{"q": {q}, "per_page": {"100"}}
+	endpoint := c.endpoint("/search/repositories", query)
+
+	var repos []repository
+	for endpoint != "" {
+		var page struct {
+			Items []repository `json:"items"`
+		}
+		next, err := c.get(ctx, endpoint, &page)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page.Items...)
+		endpoint = next
+	}
+	return toModels(repos), nil
+}
+
+// TopicQuery builds a search query for the repositories with topic, limited
+// to an organization or user when given.
+func TopicQuery(topic, org, user string) string {
+	terms := []string{"topic:" + topic}
+	if org != "" {
+		terms = append(terms, "org:"+org)
+	}
+	if user != "" {
+		terms = append(terms, "user:"+user)
+	}
+	return strings.Join(terms, " ")
+}
+
+// listPages follows the Link headers from endpoint and returns every item.
+func listPages[T any](ctx context.Context, c *Client, endpoint string) ([]T, error) {
+	var items []T
+	for endpoint != "" {
+		var page []T
+		next, err := c.get(ctx, endpoint, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		endpoint = next
+	}
+	return items, nil
+}
+
+// Languages retrieves the bytes of code per language of a repository.
+func (c *Client) Languages(ctx context.Context, repo models.Repository) (map[string]uint64, error) {
+	if repo.Project == "" || repo.Name == "" {
+		return nil, fmt.Errorf("github: repository owner and name are required")
+	}
+	endpoint := c.endpoint("/repos/"+url.PathEscape(repo.Project)+"/"+url.PathEscape(repo.Name)+"/languages", nil)
+	languages := make(map[string]uint64)
+	if _, err := c.get(ctx, endpoint, &languages); err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
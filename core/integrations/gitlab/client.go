@@ -0,0 +1,58 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/msetsma/RepoRover/core/integrations"
+)
+
+// DefaultURL is the address of gitlab.com.
+const DefaultURL = "https://gitlab.com"
+
+// Client talks to the GitLab REST API. BaseURL is the address of the
+// instance; the API lives below /api/v4. Token is a personal, group or
+// project access token; without one only public projects are visible.
+type Client struct {
+	integrations.Client
+}
+
+// NewClient returns a client for the GitLab instance at baseURL. An empty
+// baseURL means DefaultURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	c := &Client{integrations.NewClient("gitlab", baseURL, token)}
+	c.Authenticate = func(req *http.Request, token string) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	c.Header.Set("Accept", "application/json")
+	return c
+}
+
+// get requests endpoint and decodes the JSON response into v. It returns the
+// URL of the next page, if any: the Link header, which GitLab sends for both
+// offset and keyset pagination, or else the page number of X-Next-Page.
+func (c *Client) get(ctx context.Context, endpoint string, v any) (string, error) {
+	header, err := c.Get(ctx, endpoint, v)
+	if err != nil {
+		return "", err
+	}
+	if next := integrations.NextLink(header); next != "" {
+		return next, nil
+	}
+	page := header.Get("X-Next-Page")
+	if page == "" {
+		return "", nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("page", page)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
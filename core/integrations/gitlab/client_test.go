@@ -0,0 +1,150 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientGroupProjects(t *testing.T) {
+	projects := func(w http.ResponseWriter, paths ...string) {
+		var body []string
+		for _, path := range paths {
+			name := path[strings.LastIndex(path, "/")+1:]
+			body = append(body, fmt.Sprintf(`{"path": %q, "path_with_namespace": %q}`, name, path))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(body, ","))
+	}
+	// link answers with paths and a Link to the page after id, as keyset
+	// pagination does.
+	link := func(id string, paths ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if id != "" {
+				next := *r.URL
+				next.Scheme, next.Host = "http", r.Host
+				query := next.Query()
+				query.Set("id_after", id)
+				next.RawQuery = query.Encode()
+				w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+			}
+			projects(w, paths...)
+		}
+	}
+	// offset answers with paths and only X-Next-Page, as offset pagination
+	// does for large collections.
+	offset := func(page string, paths ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Next-Page", page)
+			projects(w, paths...)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		responses []http.HandlerFunc
+		want      []string
+		// pages are the id_after and page parameters of the requests.
+		pages []string
+	}{
+		{
+			name:      "Link header",
+			responses: []http.HandlerFunc{link("2", "acme/api", "acme/sub/web"), link("", "acme/cli")},
+			want:      []string{"api", "sub/web", "cli"},
+			pages:     []string{"/", "2/"},
+		},
+		{
+			name:      "X-Next-Page",
+			responses: []http.HandlerFunc{offset("2", "acme/api"), offset("", "acme/cli")},
+			want:      []string{"api", "cli"},
+			pages:     []string{"/", "/2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+					t.Errorf("PRIVATE-TOKEN = %q", got)
+				}
+				if r.URL.EscapedPath() == "/api/v4/groups/acme" {
+					fmt.Fprint(w, `{"id": 7, "full_path": "acme"}`)
+					return
+				}
+				if r.URL.EscapedPath() != "/api/v4/groups/7/projects" {
+					t.Errorf("path = %s", r.URL.EscapedPath())
+				}
+				query := r.URL.Query()
+				pages = append(pages, query.Get("id_after")+"/"+query.Get("page"))
+				if len(pages) > len(tt.responses) {
+					t.Errorf("unexpected request %d", len(pages))
+					http.NotFound(w, r)
+					return
+				}
+				tt.responses[len(pages)-1](w, r)
+			}))
+			defer srv.Close()
+
+			repos, err := NewClient(srv.URL, "secret").GroupProjects(context.Background(), "acme")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, repo := range repos {
+				paths = append(paths, repo.GroupPath)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("group paths = %v, want %v", paths, tt.want)
+			}
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Errorf("pages = %q, want %q", pages, tt.pages)
+			}
+		})
+	}
+}
+
+func TestClientGroupProjectsPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		group string
+		want  []string
+	}{
+		{"numeric id", "7", []string{"api", "sub/web", "other/cli"}},
+		{"different case", "ACME/Platform", []string{"api", "sub/web", "other/cli"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.EscapedPath() {
+				case "/api/v4/groups/" + url.PathEscape(tt.group):
+					fmt.Fprint(w, `{"id": 7, "full_path": "acme/platform"}`)
+				case "/api/v4/groups/7/projects":
+					// Shared projects keep their full path.
+					fmt.Fprint(w, `[{"path_with_namespace": "acme/platform/api"},
+						{"path_with_namespace": "Acme/Platform/sub/web"},
+						{"path_with_namespace": "other/cli"}]`)
+				default:
+					t.Errorf("path = %s", r.URL.EscapedPath())
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			repos, err := NewClient(srv.URL, "").GroupProjects(context.Background(), tt.group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, repo := range repos {
+				paths = append(paths, repo.GroupPath)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("group paths = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// project is a GitLab project, i.e. a repository, as returned by the API.
+type project struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	Namespace         namespace `json:"namespace"`
+	HTTPURLToRepo     string    `json:"http_url_to_repo"`
+	SSHURLToRepo      string    `json:"ssh_url_to_repo"`
+	DefaultBranch     string    `json:"default_branch"`
+	Archived          bool      `json:"archived"`
+	ForkedFromProject *struct {
+		ID int64 `json:"id"`
+	} `json:"forked_from_project"`
+}
+
+// namespace is the group or user a project belongs to.
+type namespace struct {
+	FullPath string `json:"full_path"`
+}
+
+// group is a GitLab group as returned by the API.
+type group struct {
+	ID       int64  `json:"id"`
+	FullPath string `json:"full_path"`
+}
+
+func (p project) model() models.Repository {
+	return models.Repository{
+		Provider:      "gitlab",
+		ProviderID:    strconv.FormatInt(p.ID, 10),
+		Name:          p.Path,
+		Project:       p.Namespace.FullPath,
+		DefaultBranch: p.DefaultBranch,
+		RemoteURL:     p.HTTPURLToRepo,
+		SSHURL:        p.SSHURLToRepo,
+		Archived:      p.Archived,
+		Fork:          p.ForkedFromProject != nil,
+		LastUpdated:   time.Now(),
+	}
+}
+
+// GroupProjects retrieves the projects of a group and all of its subgroups.
+// id is the full path of the group, e.g. acme/platform, or its numeric id. The
+// GroupPath of each repository is its path below the group, so
+// acme/platform/api becomes api. Pages are requested with keyset pagination,
+// which GitLab falls back from to offset pagination where it isn't supported.
+func (c *Client) GroupProjects(ctx context.Context, id string) ([]models.Repository, error) {
+	// The path of the group is taken from GitLab: id may be a number, and
+	// paths are matched regardless of case.
+	var g group
+	if _, err := c.get(ctx, c.BaseURL+"/api/v4/groups/"+url.PathEscape(id)+"?with_projects=false", &g); err != nil {
+		return nil, err
+	}
+	root := strings.Trim(g.FullPath, "/") + "/"
+
+	query := url.Values{
+		"include_subgroups": {"true"},
+		"with_shared":       {"false"},
+		"per_page":          {"100"},
+		"pagination":        {"keyset"},
+		"order_by":          {"id"},
+		"sort":              {"asc"},
+	}
+	endpoint := c.BaseURL + "/api/v4/groups/" + strconv.FormatInt(g.ID, 10) + "/projects?" + query.Encode()
+
+	var repos []models.Repository
+	for endpoint != "" {
+		var page []project
+		next, err := c.get(ctx, endpoint, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page {
+			repo := p.model()
+			repo.GroupPath = p.PathWithNamespace
+			if len(p.PathWithNamespace) > len(root) && strings.EqualFold(p.PathWithNamespace[:len(root)], root) {
+				repo.GroupPath = p.PathWithNamespace[len(root):]
+			}
+			repos = append(repos, repo)
+		}
+		endpoint = next
+	}
+	return repos, nil
+}
@@ -0,0 +1,35 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/integrations"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+func init() {
+	integrations.Register("gitlab", func(cfg config.Integrations) (integrations.Provider, error) {
+		return NewClient(cfg.GitLab.URL, cfg.GitLab.APIToken), nil
+	})
+}
+
+// Discover lists the projects of the group scope.Org, including those of its
+// subgroups, placed on disk like the subgroups.
+func (c *Client) Discover(ctx context.Context, scope integrations.Scope) ([]models.Repository, error) {
+	if scope.Org == "" {
+		return nil, fmt.Errorf("%w: gitlab requires a group, given as the organization", integrations.ErrInvalidScope)
+	}
+	if scope.User != "" || scope.Project != "" || scope.AllProjects || scope.Topic != "" {
+		return nil, fmt.Errorf("%w: gitlab only supports importing a group", integrations.ErrInvalidScope)
+	}
+	return c.GroupProjects(ctx, scope.Org)
+}
+
+// ValidateToken requests the authenticated user.
+func (c *Client) ValidateToken(ctx context.Context) error {
+	var user struct{}
+	_, err := c.get(ctx, c.BaseURL+"/api/v4/user", &user)
+	return err
+}
@@ -0,0 +1,100 @@
+// Package integrations defines the interface hosting providers such as Azure
+// DevOps and GitHub implement, and a registry to look them up by name.
+// Providers register themselves from their package's init function, so a
+// provider is available once its package is imported.
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+var (
+	// ErrUnknownProvider is returned by New for names no provider registered.
+	ErrUnknownProvider = errors.New("unknown provider")
+	// ErrInvalidScope is returned by providers for scopes they cannot list.
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrUnauthorized is wrapped by APIErrors for rejected or missing tokens.
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Scope selects the repositories a provider discovers. Which fields apply
+// depends on the provider.
+type Scope struct {
+	Org         string
+	User        string
+	Project     string
+	AllProjects bool
+	Topic       string
+}
+
+// Provider discovers repositories on a hosting service. Discovered
+// repositories have their Provider, ProviderID, Name, Project and URLs set;
+// their ID is left to the caller.
+type Provider interface {
+	Discover(ctx context.Context, scope Scope) ([]models.Repository, error)
+}
+
+// LanguageReporter is implemented by providers that know the languages of a
+// repository.
+type LanguageReporter interface {
+	// Languages returns the size in bytes of the code per language of a
+	// repository discovered by the provider.
+	Languages(ctx context.Context, repo models.Repository) (map[string]uint64, error)
+}
+
+// TokenValidator is implemented by providers that can check their token
+// without discovering anything.
+type TokenValidator interface {
+	// ValidateToken returns an error when the provider rejects the token.
+	ValidateToken(ctx context.Context) error
+}
+
+// Factory creates a provider from the integration settings.
+type Factory func(cfg config.Integrations) (Provider, error)
+
+var (
+	providersLock sync.RWMutex
+	providers     = make(map[string]Factory)
+)
+
+// Register makes a provider available under name. It panics if name is
+// registered twice.
+func Register(name string, factory Factory) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	if _, dup := providers[name]; dup {
+		panic("integrations: Register called twice for provider " + name)
+	}
+	providers[name] = factory
+}
+
+// Providers returns the names of the registered providers.
+func Providers() []string {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the named provider.
+func New(name string, cfg config.Integrations) (Provider, error) {
+	providersLock.RLock()
+	factory, ok := providers[name]
+	providersLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q; available: %s", ErrUnknownProvider, name, strings.Join(Providers(), ", "))
+	}
+	return factory(cfg)
+}
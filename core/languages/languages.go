@@ -0,0 +1,138 @@
+// Package languages detects the programming languages of a working tree from
+// file names, in the spirit of GitHub's linguist: vendored code, generated
+// files and prose or data formats are not counted.
+package languages
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/msetsma/RepoRover/core/git"
+)
+
+// extensions maps lower-case file extensions to languages.
+var extensions = map[string]string{
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".css":    "CSS",
+	".scss":   "SCSS",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".fs":     "F#",
+	".go":     "Go",
+	".groovy": "Groovy",
+	".hs":     "Haskell",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".java":   "Java",
+	".js":     "JavaScript",
+	".jsx":    "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".lua":    "Lua",
+	".m":      "Objective-C",
+	".php":    "PHP",
+	".pl":     "Perl",
+	".ps1":    "PowerShell",
+	".psm1":   "PowerShell",
+	".py":     "Python",
+	".r":      "R",
+	".rb":     "Ruby",
+	".rs":     "Rust",
+	".scala":  "Scala",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".swift":  "Swift",
+	".tf":     "HCL",
+	".hcl":    "HCL",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".vb":     "Visual Basic .NET",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+}
+
+// filenames maps file names without a telling extension to languages.
+var filenames = map[string]string{
+	"Dockerfile":  "Dockerfile",
+	"Makefile":    "Makefile",
+	"GNUmakefile": "Makefile",
+	"Rakefile":    "Ruby",
+	"Gemfile":     "Ruby",
+	"Jenkinsfile": "Groovy",
+}
+
+// vendored are directories holding third-party code.
+var vendored = []string{"vendor/", "node_modules/", "third_party/", "bower_components/", "Pods/"}
+
+// generated are file name suffixes of generated code.
+var generated = []string{".min.js", ".min.css", ".pb.go", "_pb2.py", ".designer.cs"}
+
+// Detect returns the language of the file at path, or an empty string when it
+// is not counted.
+func Detect(file string) string {
+	name := path.Base(filepath.ToSlash(file))
+	if lang, ok := filenames[name]; ok {
+		return lang
+	}
+	if strings.HasPrefix(name, "Dockerfile.") {
+		return "Dockerfile"
+	}
+	lower := strings.ToLower(name)
+	for _, suffix := range generated {
+		if strings.HasSuffix(lower, suffix) {
+			return ""
+		}
+	}
+	return extensions[filepath.Ext(lower)]
+}
+
+// Scan sums the size in bytes of the files tracked in the working tree at dir
+// per language.
+func Scan(ctx context.Context, dir string) (map[string]uint64, error) {
+	files, err := git.ListFiles(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint64)
+	for _, file := range files {
+		if isVendored(file) {
+			continue
+		}
+		lang := Detect(file)
+		if lang == "" {
+			continue
+		}
+		info, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil || !info.Mode().IsRegular() {
+			// Deleted but still tracked, or a symlink.
+			continue
+		}
+		sizes[lang] += uint64(info.Size())
+	}
+	return sizes, nil
+}
+
+func isVendored(file string) bool {
+	for _, dir := range vendored {
+		if strings.HasPrefix(file, dir) || strings.Contains(file, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package languages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/git"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"main.go", "Go"},
+		{"cmd/rr/MAIN.GO", "Go"},
+		{"web/app.tsx", "TypeScript"},
+		{"Dockerfile", "Dockerfile"},
+		{"build/Dockerfile.release", "Dockerfile"},
+		{"Makefile", "Makefile"},
+		{`src\Program.cs`, "C#"},
+		{"README.md", ""},
+		{"config.yaml", ""},
+		{"LICENSE", ""},
+		{"static/app.min.js", ""},
+		{"api/service.pb.go", ""},
+		{"Form1.Designer.cs", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.file); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestIsVendored(t *testing.T) {
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"vendor/github.com/pkg/errors/errors.go", true},
+		{"web/node_modules/react/index.js", true},
+		{"ios/Pods/Alamofire/Source.swift", true},
+		{"third_party/zlib/zlib.c", true},
+		{"main.go", false},
+		{"vendors/list.go", false},
+		{"internal/vendor.go", false},
+		{"myvendor/lib.go", false},
+	}
+	for _, tt := range tests {
+		if got := isVendored(tt.file); got != tt.want {
+			t.Errorf("isVendored(%q) = %t, want %t", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git.Run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(file, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "--quiet")
+	write("main.go", "package main\n")
+	write("cmd/tool.go", "package cmd\n")
+	write("scripts/build.sh", "make\n")
+	write("README.md", "# readme\n")
+	write("vendor/lib/lib.go", "package lib\n")
+	write("web/app.min.js", "x()\n")
+	write("gone.py", "print()\n")
+	if err := os.Symlink("main.go", filepath.Join(dir, "link.go")); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	// Untracked and deleted files are not counted.
+	write("untracked.go", "package main\n")
+	if err := os.Remove(filepath.Join(dir, "gone.py")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Scan(ctx, dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := map[string]uint64{"Go": 25, "Shell": 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan = %v, want %v", got, want)
+	}
+
+	if _, err := Scan(ctx, t.TempDir()); err == nil {
+		t.Error("scanning a directory outside a repository succeeded")
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+// Repository is a repository known to RepoRover, either discovered through an
+// integration or added to a group by hand.
+type Repository struct {
+	// ID identifies the repository independent of where it was found; see
+	// workspace.RepositoryID.
+	ID string `json:"id"`
+	// Provider is the integration that reported the repository, e.g. github.
+	// It is empty for repositories added by hand.
+	Provider string `json:"provider,omitempty"`
+	// ProviderID is the provider's own identifier of the repository.
+	ProviderID string `json:"providerId,omitempty"`
+	Name       string `json:"name"`
+	// Project is the project, organization, user or group the repository
+	// belongs to on its provider.
+	Project string `json:"project"`
+	// GroupPath is where the repository is placed below a group directory when
+	// the provider's hierarchy is mirrored, e.g. subgroup/name. Empty means
+	// the repository is placed by name.
+	GroupPath     string `json:"groupPath,omitempty"`
+	DefaultBranch string `json:"defaultBranch"`
+	// RemoteURL is the HTTPS clone URL; SSHURL the SSH one when known.
+	RemoteURL string `json:"remoteUrl"`
+	SSHURL    string `json:"sshUrl,omitempty"`
+	Archived  bool   `json:"archived"`
+	Fork      bool   `json:"fork"`
+	// Disabled repositories are listed by their provider but cannot be
+	// cloned.
+	Disabled    bool              `json:"disabled"`
+	Languages   map[string]uint64 `json:"languages,omitempty"`
+	LastUpdated time.Time         `json:"lastUpdated"`
+}
+
+// Commit is a single commit of a repository.
+type Commit struct {
+	SHA          string    `json:"sha"`
+	RepositoryID string    `json:"repository_id"`
+	Author       string    `json:"author"`
+	AuthorEmail  string    `json:"author_email"`
+	Date         time.Time `json:"date"`
+	Subject      string    `json:"subject"`
+	// FilesChanged, Insertions and Deletions summarise Files.
+	FilesChanged int          `json:"files_changed"`
+	Insertions   int          `json:"insertions"`
+	Deletions    int          `json:"deletions"`
+	Files        []CommitFile `json:"files,omitempty"`
+}
+
+// CommitFile is the change a commit made to a single file.
+type CommitFile struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// ActiveRepository represents a repository with its activity count
+type ActiveRepository struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	ActivityCount int       `json:"activity_count"`
+	Insertions    int       `json:"insertions"`
+	Deletions     int       `json:"deletions"`
+	LastCommit    time.Time `json:"last_commit"`
+}
+
+// AuthorActivity is the work of a single commit author.
+type AuthorActivity struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Commits    int    `json:"commits"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// WeeklyActivity is the number of commits in the week starting on Monday Week.
+type WeeklyActivity struct {
+	Week    time.Time `json:"week"`
+	Commits int       `json:"commits"`
+}
+
+// FileActivity is how often a file was changed.
+type FileActivity struct {
+	Repository string `json:"repository"`
+	Path       string `json:"path"`
+	Commits    int    `json:"commits"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// Group is a named collection of repositories.
+type Group struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	CreatedAt       time.Time `json:"created_at"`
+	RepositoryCount int       `json:"repository_count"`
+}
+
+// GroupRepository is a repository as a member of a group.
+type GroupRepository struct {
+	RepositoryID  string `json:"repository_id"`
+	Name          string `json:"name"`
+	RemoteURL     string `json:"remote_url"`
+	DefaultBranch string `json:"default_branch"`
+	// Path is the working tree on disk. It is empty while the repository
+	// has been added by URL and is still pending clone.
+	Path    string    `json:"path"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// PendingClone reports whether the repository has no working tree yet.
+func (r GroupRepository) PendingClone() bool {
+	return r.Path == ""
+}
@@ -0,0 +1,127 @@
+// Package paths resolves where RepoRover keeps its files.
+//
+// Configuration lives in the XDG config directory, the database and cloned
+// groups in the data directory. Setting ROVER_HOME places both below that one
+// directory instead, which keeps tests and CI runs away from the user's files.
+// RepoRover keeps no cache or runtime state, so the XDG cache and state
+// directories are not used.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// HomeEnv is the environment variable that overrides every location.
+const HomeEnv = "ROVER_HOME"
+
+// ConfigFileName is the name of the configuration file.
+const ConfigFileName = "rover.yaml"
+
+// Sources of a location.
+const (
+	SourceHome    = HomeEnv
+	SourceXDG     = "xdg"
+	SourceDefault = "default"
+	// SourceLegacy marks a location used by older versions of rr that is
+	// still in use because nothing exists at the current one.
+	SourceLegacy = "legacy"
+)
+
+// Location is a resolved path and why it was chosen.
+type Location struct {
+	Path   string
+	Source string
+	// Preferred is the current location of a legacy one, where its file
+	// should be moved.
+	Preferred string
+}
+
+// Paths are the resolved locations.
+type Paths struct {
+	Config     Location
+	ConfigFile Location
+	Templates  Location
+	Data       Location
+	// Groups is the default clone destination.
+	Groups Location
+
+	// home is the user's home directory, used to find legacy locations. It
+	// is empty when ROVER_HOME is set.
+	home string
+}
+
+// Resolve resolves every location from the environment.
+func Resolve() *Paths {
+	if root := os.Getenv(HomeEnv); root != "" {
+		at := func(elem ...string) Location {
+			return Location{Path: filepath.Join(append([]string{root}, elem...)...), Source: SourceHome}
+		}
+		return &Paths{
+			Config:     at("config"),
+			ConfigFile: at("config", ConfigFileName),
+			Templates:  at("config", "templates"),
+			Data:       at("data"),
+			Groups:     at("data", "groups"),
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	p := &Paths{
+		Config: xdg("XDG_CONFIG_HOME", home, ".config"),
+		Data:   xdg("XDG_DATA_HOME", home, ".local", "share"),
+		home:   home,
+	}
+	p.ConfigFile = existing(p.Config.join(ConfigFileName),
+		filepath.Join(home, ".config", ConfigFileName),
+		filepath.Join(home, ".config", "rover", ConfigFileName))
+	p.Templates = p.Config.join("templates")
+	p.Groups = existing(p.Data.join("groups"), p.Config.join("groups").Path)
+	return p
+}
+
+// DatabaseFile returns the location of the named SQLite database.
+func (p *Paths) DatabaseFile(name string) Location {
+	current := p.Data.join(name + ".sqlite")
+	if p.home == "" {
+		return current
+	}
+	legacy := filepath.Join(p.home, ".config", ".reporover", "db", name+".sqlite")
+	if dir, err := os.UserConfigDir(); err == nil {
+		legacy = filepath.Join(dir, ".reporover", "db", name+".sqlite")
+	}
+	return existing(current, legacy)
+}
+
+func (l Location) join(elem ...string) Location {
+	return Location{Path: filepath.Join(append([]string{l.Path}, elem...)...), Source: l.Source}
+}
+
+// xdg returns the rover directory below the XDG base directory named by env,
+// or below its default relative to home.
+func xdg(env, home string, fallback ...string) Location {
+	if dir := os.Getenv(env); dir != "" && filepath.IsAbs(dir) {
+		return Location{Path: filepath.Join(dir, "rover"), Source: SourceXDG}
+	}
+	return Location{Path: filepath.Join(append(append([]string{home}, fallback...), "rover")...), Source: SourceDefault}
+}
+
+// existing returns current unless it doesn't exist and one of the legacy
+// paths does, so files written by older versions keep being found.
+func existing(current Location, legacy ...string) Location {
+	if _, err := os.Stat(current.Path); err == nil {
+		return current
+	}
+	for _, path := range legacy {
+		if path == current.Path {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return Location{Path: path, Source: SourceLegacy, Preferred: current.Path}
+		}
+	}
+	return current
+}
@@ -0,0 +1,198 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setHome points the resolver at a fresh home directory without ROVER_HOME
+// or XDG variables and returns it.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{HomeEnv, "XDG_CONFIG_HOME", "XDG_DATA_HOME"} {
+		t.Setenv(env, "")
+	}
+	return home
+}
+
+func touch(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup prepares the environment below home and returns the
+		// expected locations.
+		setup func(t *testing.T, home string) map[string]Location
+	}{
+		{
+			name: "defaults",
+			setup: func(t *testing.T, home string) map[string]Location {
+				config := filepath.Join(home, ".config", "rover")
+				data := filepath.Join(home, ".local", "share", "rover")
+				return map[string]Location{
+					"config":      {Path: config, Source: SourceDefault},
+					"config file": {Path: filepath.Join(config, ConfigFileName), Source: SourceDefault},
+					"templates":   {Path: filepath.Join(config, "templates"), Source: SourceDefault},
+					"data":        {Path: data, Source: SourceDefault},
+					"groups":      {Path: filepath.Join(data, "groups"), Source: SourceDefault},
+				}
+			},
+		},
+		{
+			name: "ROVER_HOME",
+			setup: func(t *testing.T, home string) map[string]Location {
+				root := filepath.Join(home, "ci")
+				t.Setenv(HomeEnv, root)
+				t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+				// Legacy files are not looked for below ROVER_HOME.
+				touch(t, filepath.Join(home, ".config", ConfigFileName))
+				return map[string]Location{
+					"config":      {Path: filepath.Join(root, "config"), Source: SourceHome},
+					"config file": {Path: filepath.Join(root, "config", ConfigFileName), Source: SourceHome},
+					"templates":   {Path: filepath.Join(root, "config", "templates"), Source: SourceHome},
+					"data":        {Path: filepath.Join(root, "data"), Source: SourceHome},
+					"groups":      {Path: filepath.Join(root, "data", "groups"), Source: SourceHome},
+				}
+			},
+		},
+		{
+			name: "XDG",
+			setup: func(t *testing.T, home string) map[string]Location {
+				config := filepath.Join(home, "xdg-config")
+				t.Setenv("XDG_CONFIG_HOME", config)
+				// Relative directories are invalid and ignored.
+				t.Setenv("XDG_DATA_HOME", "relative")
+				data := filepath.Join(home, ".local", "share", "rover")
+				return map[string]Location{
+					"config":      {Path: filepath.Join(config, "rover"), Source: SourceXDG},
+					"config file": {Path: filepath.Join(config, "rover", ConfigFileName), Source: SourceXDG},
+					"data":        {Path: data, Source: SourceDefault},
+					"groups":      {Path: filepath.Join(data, "groups"), Source: SourceDefault},
+				}
+			},
+		},
+		{
+			name: "legacy",
+			setup: func(t *testing.T, home string) map[string]Location {
+				config := filepath.Join(home, ".config", "rover")
+				legacyFile := filepath.Join(home, ".config", ConfigFileName)
+				legacyGroups := filepath.Join(config, "groups")
+				touch(t, legacyFile)
+				touch(t, filepath.Join(legacyGroups, "backend", "README"))
+				return map[string]Location{
+					"config file": {Path: legacyFile, Source: SourceLegacy, Preferred: filepath.Join(config, ConfigFileName)},
+					"groups": {
+						Path:      legacyGroups,
+						Source:    SourceLegacy,
+						Preferred: filepath.Join(home, ".local", "share", "rover", "groups"),
+					},
+				}
+			},
+		},
+		{
+			name: "current and legacy",
+			setup: func(t *testing.T, home string) map[string]Location {
+				current := filepath.Join(home, ".config", "rover", ConfigFileName)
+				touch(t, current)
+				touch(t, filepath.Join(home, ".config", ConfigFileName))
+				return map[string]Location{
+					"config file": {Path: current, Source: SourceDefault},
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.setup(t, setHome(t))
+			p := Resolve()
+			got := map[string]Location{
+				"config":      p.Config,
+				"config file": p.ConfigFile,
+				"templates":   p.Templates,
+				"data":        p.Data,
+				"groups":      p.Groups,
+			}
+			for name, location := range want {
+				if got[name] != location {
+					t.Errorf("%s = %+v, want %+v", name, got[name], location)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabaseFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, home string) Location
+	}{
+		{
+			name: "new",
+			setup: func(t *testing.T, home string) Location {
+				return Location{Path: filepath.Join(home, ".local", "share", "rover", "rover.sqlite"), Source: SourceDefault}
+			},
+		},
+		{
+			name: "legacy",
+			setup: func(t *testing.T, home string) Location {
+				legacy := filepath.Join(home, ".config", ".reporover", "db", "rover.sqlite")
+				touch(t, legacy)
+				return Location{
+					Path:      legacy,
+					Source:    SourceLegacy,
+					Preferred: filepath.Join(home, ".local", "share", "rover", "rover.sqlite"),
+				}
+			},
+		},
+		{
+			name: "legacy below XDG_CONFIG_HOME",
+			setup: func(t *testing.T, home string) Location {
+				config := filepath.Join(home, "xdg-config")
+				t.Setenv("XDG_CONFIG_HOME", config)
+				legacy := filepath.Join(config, ".reporover", "db", "rover.sqlite")
+				touch(t, legacy)
+				return Location{
+					Path:      legacy,
+					Source:    SourceLegacy,
+					Preferred: filepath.Join(home, ".local", "share", "rover", "rover.sqlite"),
+				}
+			},
+		},
+		{
+			name: "current and legacy",
+			setup: func(t *testing.T, home string) Location {
+				current := filepath.Join(home, ".local", "share", "rover", "rover.sqlite")
+				touch(t, current)
+				touch(t, filepath.Join(home, ".config", ".reporover", "db", "rover.sqlite"))
+				return Location{Path: current, Source: SourceDefault}
+			},
+		},
+		{
+			name: "ROVER_HOME",
+			setup: func(t *testing.T, home string) Location {
+				t.Setenv(HomeEnv, filepath.Join(home, "ci"))
+				touch(t, filepath.Join(home, ".config", ".reporover", "db", "rover.sqlite"))
+				return Location{Path: filepath.Join(home, "ci", "data", "rover.sqlite"), Source: SourceHome}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.setup(t, setHome(t))
+			if got := Resolve().DatabaseFile("rover"); got != want {
+				t.Errorf("DatabaseFile = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// CommitFilter selects the commits an analysis covers. Zero fields don't
+// filter.
+type CommitFilter struct {
+	// Group limits the analysis to the repositories of a group, which are
+	// then reported by their name in the group.
+	Group string
+	Since time.Time
+	// Until is exclusive.
+	Until time.Time
+	// Author matches part of the author's name or email, ignoring case.
+	Author string
+}
+
+// join returns the join needed to filter by group.
+func (f CommitFilter) join() string {
+	if f.Group == "" {
+		return ""
+	}
+	return "JOIN group_repositories gr ON gr.repository_id = r.id"
+}
+
+// nameColumn returns the column repositories are reported by.
+func (f CommitFilter) nameColumn() string {
+	if f.Group == "" {
+		return "r.name"
+	}
+	return "gr.name"
+}
+
+// where returns the condition and arguments selecting the commits c of the
+// repositories r matching f.
+func (f CommitFilter) where(d *Database) (string, []any, error) {
+	conditions := []string{"1 = 1"}
+	var args []any
+	if f.Group != "" {
+		id, err := groupID(d.db, f.Group)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "gr.group_id = ?")
+		args = append(args, id)
+	}
+	if !f.Since.IsZero() {
+		conditions = append(conditions, "c.date >= ?")
+		args = append(args, f.Since.UTC().Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		conditions = append(conditions, "c.date < ?")
+		args = append(args, f.Until.UTC().Format(time.RFC3339))
+	}
+	if f.Author != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(f.Author) + "%"
+		conditions = append(conditions, `(c.author LIKE ? ESCAPE '\' OR c.author_email LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// TopAuthors retrieves the authors with the most commits matching f. Authors
+// are told apart by email address.
+func (d *Database) TopAuthors(f CommitFilter, limit int) ([]models.AuthorActivity, error) {
+	where, args, err := f.where(d)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+	SELECT MAX(c.author), MAX(c.author_email), COUNT(c.id) AS commit_count,
+		COALESCE(SUM(c.insertions), 0), COALESCE(SUM(c.deletions), 0)
+	FROM commits c
+	JOIN repositories r ON c.repository_id = r.id
+	` + f.join() + `
+	WHERE ` + where + `
+	GROUP BY LOWER(c.author_email)
+	ORDER BY commit_count DESC, 1
+	LIMIT ?
+	`
+	rows, err := d.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []models.AuthorActivity
+	for rows.Next() {
+		var a models.AuthorActivity
+		if err := rows.Scan(&a.Name, &a.Email, &a.Commits, &a.Insertions, &a.Deletions); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// CommitsPerWeek retrieves the number of commits matching f per week, oldest
+// week first. Weeks without commits are left out.
+func (d *Database) CommitsPerWeek(f CommitFilter) ([]models.WeeklyActivity, error) {
+	where, args, err := f.where(d)
+	if err != nil {
+		return nil, err
+	}
+	// Moving to the next Sunday and back six days lands on the Monday
+	// starting the week.
+	query := `
+	SELECT date(c.date, 'weekday 0', '-6 days') AS week, COUNT(c.id)
+	FROM commits c
+	JOIN repositories r ON c.repository_id = r.id
+	` + f.join() + `
+	WHERE ` + where + `
+	GROUP BY week
+	ORDER BY week
+	`
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying commits per week: %w", err)
+	}
+	defer rows.Close()
+
+	var weeks []models.WeeklyActivity
+	for rows.Next() {
+		var w models.WeeklyActivity
+		var week string
+		if err := rows.Scan(&week, &w.Commits); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if w.Week, err = time.Parse(time.DateOnly, week); err != nil {
+			return nil, fmt.Errorf("error parsing week: %w", err)
+		}
+		weeks = append(weeks, w)
+	}
+	return weeks, rows.Err()
+}
+
+// BusiestFiles retrieves the files changed by the most commits matching f.
+func (d *Database) BusiestFiles(f CommitFilter, limit int) ([]models.FileActivity, error) {
+	where, args, err := f.where(d)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+	SELECT ` + f.nameColumn() + `, cf.path, COUNT(*) AS change_count,
+		SUM(cf.insertions), SUM(cf.deletions)
+	FROM commit_files cf
+	JOIN commits c ON cf.commit_id = c.id
+	JOIN repositories r ON c.repository_id = r.id
+	` + f.join() + `
+	WHERE ` + where + `
+	GROUP BY r.id, cf.path
+	ORDER BY change_count DESC, 1, cf.path
+	LIMIT ?
+	`
+	rows, err := d.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying busiest files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []models.FileActivity
+	for rows.Next() {
+		var file models.FileActivity
+		if err := rows.Scan(&file.Repository, &file.Path, &file.Commits, &file.Insertions, &file.Deletions); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// StaleRepositories retrieves the repositories, of group when it isn't empty,
+// whose latest ingested commit is older than before, oldest first.
+// Repositories without ingested commits are included first with a zero
+// LastCommit.
+func (d *Database) StaleRepositories(group string, before time.Time) ([]models.ActiveRepository, error) {
+	f := CommitFilter{Group: group}
+	where, args, err := f.where(d)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+	SELECT r.id, ` + f.nameColumn() + `, COUNT(c.id),
+		COALESCE(SUM(c.insertions), 0), COALESCE(SUM(c.deletions), 0), MAX(c.date) AS last_commit
+	FROM repositories r
+	LEFT JOIN commits c ON c.repository_id = r.id
+	` + f.join() + `
+	WHERE ` + where + `
+	GROUP BY r.id
+	HAVING last_commit IS NULL OR last_commit < ?
+	ORDER BY last_commit IS NOT NULL, last_commit, 2
+	`
+	args = append(args, before.UTC().Format(time.RFC3339))
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying stale repositories: %w", err)
+	}
+	defer rows.Close()
+
+	var stale []models.ActiveRepository
+	for rows.Next() {
+		var repo models.ActiveRepository
+		var lastCommit sql.NullString
+		if err := rows.Scan(&repo.ID, &repo.Name, &repo.ActivityCount, &repo.Insertions, &repo.Deletions, &lastCommit); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if lastCommit.Valid {
+			if repo.LastCommit, err = time.Parse(time.RFC3339, lastCommit.String); err != nil {
+				return nil, fmt.Errorf("error parsing commit date: %w", err)
+			}
+		}
+		stale = append(stale, repo)
+	}
+	return stale, rows.Err()
+}
@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// monday starts the week most seeded commits fall in.
+var monday = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+// seedCommits creates the groups backend, with api and worker, and frontend,
+// with web, and commits to them.
+func seedCommits(t *testing.T) *Database {
+	t.Helper()
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api", "worker")
+	addGroup(t, d, "frontend", "web")
+
+	upper := testCommit("c3", "Alice", monday.AddDate(0, 0, 8), "README", 1, 0)
+	upper.AuthorEmail = "ALICE@example.com"
+	commits := map[string][]models.Commit{
+		"api": {
+			testCommit("c1", "alice", monday, "main.go", 10, 0),
+			testCommit("c2", "bob", monday.AddDate(0, 0, 1), "main.go", 2, 1),
+			upper,
+		},
+		"worker": {testCommit("w1", "bob", monday.AddDate(0, 0, 2), "job.go", 5, 5)},
+		"web":    {testCommit("x1", "carol", monday, "app.js", 3, 0)},
+	}
+	for repo, cs := range commits {
+		if _, err := d.SaveCommits(repo, "main", cs[len(cs)-1].SHA, cs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return d
+}
+
+func TestMostActiveRepositories(t *testing.T) {
+	d := seedCommits(t)
+	tests := []struct {
+		name   string
+		filter CommitFilter
+		want   []models.ActiveRepository
+	}{
+		{
+			name:   "group",
+			filter: CommitFilter{Group: "backend"},
+			want: []models.ActiveRepository{
+				{ID: "api", Name: "api", ActivityCount: 3, Insertions: 13, Deletions: 1, LastCommit: monday.AddDate(0, 0, 8)},
+				{ID: "worker", Name: "worker", ActivityCount: 1, Insertions: 5, Deletions: 5, LastCommit: monday.AddDate(0, 0, 2)},
+			},
+		},
+		{
+			name:   "window",
+			filter: CommitFilter{Group: "backend", Since: monday.AddDate(0, 0, 1), Until: monday.AddDate(0, 0, 8)},
+			want: []models.ActiveRepository{
+				{ID: "api", Name: "api", ActivityCount: 1, Insertions: 2, Deletions: 1, LastCommit: monday.AddDate(0, 0, 1)},
+				{ID: "worker", Name: "worker", ActivityCount: 1, Insertions: 5, Deletions: 5, LastCommit: monday.AddDate(0, 0, 2)},
+			},
+		},
+		{
+			name:   "author",
+			filter: CommitFilter{Author: "CAROL"},
+			want: []models.ActiveRepository{
+				{ID: "web", Name: "web", ActivityCount: 1, Insertions: 3, LastCommit: monday},
+			},
+		},
+		{
+			name:   "empty window",
+			filter: CommitFilter{Group: "frontend", Since: monday.AddDate(0, 0, 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.MostActiveRepositories(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repositories = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopAuthors(t *testing.T) {
+	d := seedCommits(t)
+	tests := []struct {
+		name   string
+		filter CommitFilter
+		limit  int
+		want   []models.AuthorActivity
+	}{
+		{
+			// Addresses differing in case are one author.
+			name:   "group",
+			filter: CommitFilter{Group: "backend"},
+			limit:  10,
+			want: []models.AuthorActivity{
+				{Name: "alice", Email: "alice@example.com", Commits: 2, Insertions: 11},
+				{Name: "bob", Email: "bob@example.com", Commits: 2, Insertions: 7, Deletions: 6},
+			},
+		},
+		{
+			name:   "limit",
+			filter: CommitFilter{},
+			limit:  1,
+			want:   []models.AuthorActivity{{Name: "alice", Email: "alice@example.com", Commits: 2, Insertions: 11}},
+		},
+		{
+			name:   "author",
+			filter: CommitFilter{Author: "bob@"},
+			limit:  10,
+			want:   []models.AuthorActivity{{Name: "bob", Email: "bob@example.com", Commits: 2, Insertions: 7, Deletions: 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.TopAuthors(tt.filter, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authors = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitsPerWeek(t *testing.T) {
+	d := seedCommits(t)
+	weekStart := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter CommitFilter
+		want   []models.WeeklyActivity
+	}{
+		{
+			name:   "group",
+			filter: CommitFilter{Group: "backend"},
+			want:   []models.WeeklyActivity{{Week: weekStart, Commits: 3}, {Week: weekStart.AddDate(0, 0, 7), Commits: 1}},
+		},
+		{
+			name:   "everything",
+			filter: CommitFilter{},
+			want:   []models.WeeklyActivity{{Week: weekStart, Commits: 4}, {Week: weekStart.AddDate(0, 0, 7), Commits: 1}},
+		},
+		{
+			name:   "window",
+			filter: CommitFilter{Group: "backend", Until: monday.AddDate(0, 0, 6)},
+			want:   []models.WeeklyActivity{{Week: weekStart, Commits: 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.CommitsPerWeek(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("weeks = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommitsPerWeekSunday checks that Sunday ends the week instead of
+// starting one.
+func TestCommitsPerWeekSunday(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api")
+	sunday := monday.AddDate(0, 0, 6)
+	if _, err := d.SaveCommits("api", "main", "s1", []models.Commit{testCommit("s1", "alice", sunday, "main.go", 1, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.CommitsPerWeek(CommitFilter{Group: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.WeeklyActivity{{Week: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Commits: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("weeks = %+v, want %+v", got, want)
+	}
+}
+
+func TestBusiestFiles(t *testing.T) {
+	d := seedCommits(t)
+	got, err := d.BusiestFiles(CommitFilter{Group: "backend"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.FileActivity{
+		{Repository: "api", Path: "main.go", Commits: 2, Insertions: 12, Deletions: 1},
+		{Repository: "api", Path: "README", Commits: 1, Insertions: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %+v, want %+v", got, want)
+	}
+}
+
+func TestCommitFilterUnknownGroup(t *testing.T) {
+	d := seedCommits(t)
+	if _, err := d.MostActiveRepositories(CommitFilter{Group: "missing"}); err == nil {
+		t.Error("an unknown group succeeded")
+	}
+}
+
+func TestStaleRepositories(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "old", "fresh", "never")
+	addGroup(t, d, "frontend", "elsewhere")
+	now := time.Now().UTC().Truncate(time.Second)
+	before := now.AddDate(0, -6, 0)
+
+	lastCommits := map[string]time.Time{
+		"old":       now.AddDate(-1, 0, 0),
+		"fresh":     now.AddDate(0, 0, -1),
+		"elsewhere": now.AddDate(-2, 0, 0),
+	}
+	for repo, date := range lastCommits {
+		if _, err := d.SaveCommits(repo, "main", "c-"+repo, []models.Commit{testCommit("c-"+repo, "alice", date, "main.go", 1, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Staleness goes by the commits, not by when metadata was last fetched.
+	lastUpdated := map[string]time.Time{"old": now, "fresh": now.AddDate(-3, 0, 0)}
+	for repo, updated := range lastUpdated {
+		if err := d.SaveRepository(&models.Repository{ID: repo, Name: repo, LastUpdated: updated}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		group string
+		want  []models.ActiveRepository
+	}{
+		{
+			group: "backend",
+			want: []models.ActiveRepository{
+				{ID: "never", Name: "never"},
+				{ID: "old", Name: "old", ActivityCount: 1, Insertions: 1, LastCommit: lastCommits["old"]},
+			},
+		},
+		{
+			group: "",
+			want: []models.ActiveRepository{
+				{ID: "never", Name: "never"},
+				{ID: "elsewhere", Name: "elsewhere", ActivityCount: 1, Insertions: 1, LastCommit: lastCommits["elsewhere"]},
+				{ID: "old", Name: "old", ActivityCount: 1, Insertions: 1, LastCommit: lastCommits["old"]},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := d.StaleRepositories(tt.group, before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("stale repositories of %q = %+v, want %+v", tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestActiveRepositoriesWindow(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "busy", "quiet")
+	now := time.Now().UTC().Truncate(time.Second)
+	commits := map[string][]models.Commit{
+		"busy": {
+			testCommit("b1", "alice", now.AddDate(0, 0, -2), "main.go", 1, 0),
+			testCommit("b2", "alice", now.AddDate(0, 0, -10), "main.go", 1, 0),
+			testCommit("b3", "alice", now.AddDate(0, 0, -45), "main.go", 1, 0),
+		},
+		"quiet": {testCommit("q1", "bob", now.AddDate(0, 0, -40), "main.go", 1, 0)},
+	}
+	for repo, cs := range commits {
+		if _, err := d.SaveCommits(repo, "main", cs[0].SHA, cs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		days int
+		want map[string]int
+	}{
+		{30, map[string]int{"busy": 2}},
+		{60, map[string]int{"busy": 3, "quiet": 1}},
+		{1, map[string]int{}},
+	}
+	for _, tt := range tests {
+		got, err := d.MostActiveRepositories(CommitFilter{Group: "backend", Since: now.AddDate(0, 0, -tt.days)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := map[string]int{}
+		for _, repo := range got {
+			counts[repo.Name] = repo.ActivityCount
+		}
+		if !reflect.DeepEqual(counts, tt.want) {
+			t.Errorf("active in %d days = %v, want %v", tt.days, counts, tt.want)
+		}
+	}
+}
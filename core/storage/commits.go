@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// commitID is the key of a commit in the commits table. The same commit can
+// belong to several repositories, e.g. forks, so the repository is part of it.
+func commitID(repositoryID, sha string) string {
+	return repositoryID + "@" + sha
+}
+
+// IngestedBranches returns the last ingested commit per branch of a
+// repository.
+func (d *Database) IngestedBranches(repositoryID string) (map[string]string, error) {
+	rows, err := d.db.Query(`SELECT branch, sha FROM ingested_branches WHERE repository_id = ?`, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying ingested branches: %w", err)
+	}
+	defer rows.Close()
+
+	branches := make(map[string]string)
+	for rows.Next() {
+		var branch, sha string
+		if err := rows.Scan(&branch, &sha); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		branches[branch] = sha
+	}
+	return branches, rows.Err()
+}
+
+// SaveCommits stores the commits read from branch of a repository and records
+// head as the last ingested commit of the branch. Commits that are already
+// stored are left as they are. It returns the number of commits added.
+func (d *Database) SaveCommits(repositoryID, branch, head string, commits []models.Commit) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertCommit, err := tx.Prepare(`
+	INSERT INTO commits (id, repository_id, sha, author, author_email, date, subject, files_changed, insertions, deletions)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO NOTHING
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer insertCommit.Close()
+	insertFile, err := tx.Prepare(`
+	INSERT INTO commit_files (commit_id, path, insertions, deletions)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT(commit_id, path) DO NOTHING
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer insertFile.Close()
+
+	added := 0
+	for _, c := range commits {
+		id := commitID(repositoryID, c.SHA)
+		res, err := insertCommit.Exec(id, repositoryID, c.SHA, c.Author, c.AuthorEmail,
+			c.Date.UTC().Format(time.RFC3339), c.Subject, c.FilesChanged, c.Insertions, c.Deletions)
+		if err != nil {
+			return 0, fmt.Errorf("error saving commit %s: %w", c.SHA, err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			continue
+		}
+		added++
+		for _, f := range c.Files {
+			if _, err := insertFile.Exec(id, f.Path, f.Insertions, f.Deletions); err != nil {
+				return 0, fmt.Errorf("error saving files of commit %s: %w", c.SHA, err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(`
+	INSERT INTO ingested_branches (repository_id, branch, sha, ingested_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT(repository_id, branch) DO UPDATE SET
+		sha=excluded.sha,
+		ingested_at=excluded.ingested_at
+	`, repositoryID, branch, head, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("error recording ingested branch '%s': %w", branch, err)
+	}
+
+	return added, tx.Commit()
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// testCommit returns a commit by author of one file on day.
+func testCommit(sha, author string, day time.Time, file string, insertions, deletions int) models.Commit {
+	return models.Commit{
+		SHA:          sha,
+		Author:       author,
+		AuthorEmail:  author + "@example.com",
+		Date:         day,
+		Subject:      "change " + sha,
+		FilesChanged: 1,
+		Insertions:   insertions,
+		Deletions:    deletions,
+		Files:        []models.CommitFile{{Path: file, Insertions: insertions, Deletions: deletions}},
+	}
+}
+
+func TestSaveCommits(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api")
+	day := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	c1 := testCommit("c1", "alice", day, "main.go", 10, 0)
+	c2 := testCommit("c2", "bob", day.AddDate(0, 0, 1), "main.go", 2, 1)
+	c3 := testCommit("c3", "alice", day.AddDate(0, 0, 2), "README", 1, 0)
+
+	if branches, err := d.IngestedBranches("api"); err != nil || len(branches) != 0 {
+		t.Fatalf("before ingesting: branches = %v, %v", branches, err)
+	}
+
+	steps := []struct {
+		branch, head string
+		commits      []models.Commit
+		wantAdded    int
+		wantBranches map[string]string
+	}{
+		{"main", "c2", []models.Commit{c2, c1}, 2, map[string]string{"main": "c2"}},
+		// The next read only has the commits after c2.
+		{"main", "c3", []models.Commit{c3}, 1, map[string]string{"main": "c3"}},
+		// A branch containing stored commits adds none of them again.
+		{"feature", "c2", []models.Commit{c2, c1}, 0, map[string]string{"main": "c3", "feature": "c2"}},
+		// Nothing new still moves the head forward.
+		{"feature", "c3", nil, 0, map[string]string{"main": "c3", "feature": "c3"}},
+	}
+	for i, step := range steps {
+		added, err := d.SaveCommits("api", step.branch, step.head, step.commits)
+		if err != nil {
+			t.Fatalf("step %d: SaveCommits: %v", i, err)
+		}
+		if added != step.wantAdded {
+			t.Errorf("step %d: added = %d, want %d", i, added, step.wantAdded)
+		}
+		branches, err := d.IngestedBranches("api")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(branches, step.wantBranches) {
+			t.Errorf("step %d: branches = %v, want %v", i, branches, step.wantBranches)
+		}
+	}
+
+	var commits, files int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM commits WHERE repository_id = 'api'`).Scan(&commits); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM commit_files`).Scan(&files); err != nil {
+		t.Fatal(err)
+	}
+	if commits != 3 || files != 3 {
+		t.Errorf("stored %d commits with %d files, want 3 and 3", commits, files)
+	}
+}
+
+func TestSaveCommitsPerRepository(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api", "fork")
+	c1 := testCommit("c1", "alice", time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), "main.go", 1, 0)
+
+	// A fork shares commits with its upstream; both keep their own copy.
+	for _, repo := range []string{"api", "fork"} {
+		if added, err := d.SaveCommits(repo, "main", "c1", []models.Commit{c1}); err != nil || added != 1 {
+			t.Errorf("%s: added = %d, %v; want 1", repo, added, err)
+		}
+	}
+	if branches, err := d.IngestedBranches("fork"); err != nil || branches["main"] != "c1" || len(branches) != 1 {
+		t.Errorf("fork branches = %v, %v", branches, err)
+	}
+}
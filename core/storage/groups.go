@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+var (
+	// ErrGroupNotFound is returned when a group does not exist.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrGroupExists is returned when creating a group whose name is taken.
+	ErrGroupExists = errors.New("group already exists")
+	// ErrRepositoryNotFound is returned when a repository is not part of a group.
+	ErrRepositoryNotFound = errors.New("repository not found in group")
+	// ErrRepositoryExists is returned when a repository is already part of a group.
+	ErrRepositoryExists = errors.New("repository already in group")
+)
+
+// validGroupName restricts group names to what is safe to use as a
+// directory name under the clone destination.
+var validGroupName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// groupID looks up the id of the named group.
+func groupID(q querier, name string) (int64, error) {
+	var id int64
+	err := q.QueryRow(`SELECT id FROM groups WHERE name = ?`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error looking up group '%s': %w", name, err)
+	}
+	return id, nil
+}
+
+// CreateGroup creates a new, empty group.
+func (d *Database) CreateGroup(name string) error {
+	if !validGroupName.MatchString(name) {
+		return fmt.Errorf("invalid group name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	if _, err := groupID(d.db, name); err == nil {
+		return fmt.Errorf("%w: %s", ErrGroupExists, name)
+	} else if !errors.Is(err, ErrGroupNotFound) {
+		return err
+	}
+
+	query := `INSERT INTO groups (name, created_at) VALUES (?, ?)`
+	if _, err := d.db.Exec(query, name, time.Now().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("error creating group '%s': %w", name, err)
+	}
+	return nil
+}
+
+// ListGroups retrieves all groups along with the number of repositories in each.
+func (d *Database) ListGroups() ([]models.Group, error) {
+	query := `
+	SELECT g.id, g.name, g.created_at, COUNT(gr.repository_id)
+	FROM groups g
+	LEFT JOIN group_repositories gr ON gr.group_id = g.id
+	GROUP BY g.id, g.name, g.created_at
+	ORDER BY g.name
+	`
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		var createdAt string
+		if err := rows.Scan(&group.ID, &group.Name, &createdAt, &group.RepositoryCount); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		group.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing created at timestamp: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+// AddRepository adds a repository to the named group. The repository is
+// recorded in the repositories table if it isn't known yet; existing
+// repository metadata is left untouched.
+func (d *Database) AddRepository(group string, repo models.GroupRepository) error {
+	if repo.RepositoryID == "" || repo.Name == "" {
+		return fmt.Errorf("repository id and name are required")
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := groupID(tx, group)
+	if err != nil {
+		return err
+	}
+
+	var exists int
+	err = tx.QueryRow(`
+	SELECT COUNT(*) FROM group_repositories
+	WHERE group_id = ? AND (repository_id = ? OR name = ?)
+	`, id, repo.RepositoryID, repo.Name).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error checking group membership: %w", err)
+	}
+	if exists > 0 {
+		return fmt.Errorf("%w: %s", ErrRepositoryExists, repo.Name)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	_, err = tx.Exec(`
+	INSERT INTO repositories (id, name, default_branch, remote_url, last_updated)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO NOTHING
+	`, repo.RepositoryID, repo.Name, repo.DefaultBranch, repo.RemoteURL, now)
+	if err != nil {
+		return fmt.Errorf("error saving repository '%s': %w", repo.Name, err)
+	}
+
+	_, err = tx.Exec(`
+	INSERT INTO group_repositories (group_id, repository_id, name, path, added_at)
+	VALUES (?, ?, ?, ?, ?)
+	`, id, repo.RepositoryID, repo.Name, repo.Path, now)
+	if err != nil {
+		return fmt.Errorf("error adding repository '%s' to group '%s': %w", repo.Name, group, err)
+	}
+
+	return tx.Commit()
+}
+
+// RemoveRepository removes the repository with the given name from a group.
+// The repository itself stays known so other groups are unaffected.
+func (d *Database) RemoveRepository(group, name string) error {
+	id, err := groupID(d.db, group)
+	if err != nil {
+		return err
+	}
+
+	res, err := d.db.Exec(`DELETE FROM group_repositories WHERE group_id = ? AND name = ?`, id, name)
+	if err != nil {
+		return fmt.Errorf("error removing repository '%s' from group '%s': %w", name, group, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrRepositoryNotFound, name)
+	}
+	return nil
+}
+
+// GroupRepositories retrieves the repositories that belong to a group.
+func (d *Database) GroupRepositories(group string) ([]models.GroupRepository, error) {
+	id, err := groupID(d.db, group)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT gr.repository_id, gr.name, r.remote_url, r.default_branch, gr.path, gr.added_at
+	FROM group_repositories gr
+	JOIN repositories r ON r.id = gr.repository_id
+	WHERE gr.group_id = ?
+	ORDER BY gr.name
+	`
+	rows, err := d.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying group repositories: %w", err)
+	}
+	defer rows.Close()
+
+	var repos []models.GroupRepository
+	for rows.Next() {
+		var repo models.GroupRepository
+		var remoteURL, defaultBranch sql.NullString
+		var addedAt string
+		if err := rows.Scan(&repo.RepositoryID, &repo.Name, &remoteURL, &defaultBranch, &repo.Path, &addedAt); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		repo.RemoteURL = remoteURL.String
+		repo.DefaultBranch = defaultBranch.String
+		repo.AddedAt, err = time.Parse(time.RFC3339, addedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing added at timestamp: %w", err)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, rows.Err()
+}
+
+// SetRepositoryPath records where the working tree of a group repository lives.
+func (d *Database) SetRepositoryPath(group, name, path string) error {
+	id, err := groupID(d.db, group)
+	if err != nil {
+		return err
+	}
+
+	res, err := d.db.Exec(`UPDATE group_repositories SET path = ? WHERE group_id = ? AND name = ?`, path, id, name)
+	if err != nil {
+		return fmt.Errorf("error updating path of '%s': %w", name, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrRepositoryNotFound, name)
+	}
+	return nil
+}
+
+// DeleteGroup deletes a group and its memberships. Repositories stay known.
+func (d *Database) DeleteGroup(name string) error {
+	res, err := d.db.Exec(`DELETE FROM groups WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("error deleting group '%s': %w", name, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+	}
+	return nil
+}
+
+// GroupExists reports whether the named group exists.
+func (d *Database) GroupExists(name string) (bool, error) {
+	_, err := groupID(d.db, name)
+	if errors.Is(err, ErrGroupNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
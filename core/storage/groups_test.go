@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// newDatabase opens an empty database in a temporary directory.
+func newDatabase(t *testing.T) *Database {
+	t.Helper()
+	d, err := Open(filepath.Join(t.TempDir(), "rover.sqlite"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+// addGroup creates group with a repository for each of names, whose id is
+// the name.
+func addGroup(t *testing.T, d *Database, group string, names ...string) {
+	t.Helper()
+	if err := d.CreateGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		repo := models.GroupRepository{RepositoryID: name, Name: name, RemoteURL: "https://example.com/" + name + ".git"}
+		if err := d.AddRepository(group, repo); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	d := newDatabase(t)
+	if err := d.CreateGroup("backend"); err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if err := d.CreateGroup("backend"); !errors.Is(err, ErrGroupExists) {
+		t.Errorf("creating it again: err = %v, want %v", err, ErrGroupExists)
+	}
+	for _, name := range []string{"", "../etc", "-rf", "a b", ".hidden"} {
+		if err := d.CreateGroup(name); err == nil {
+			t.Errorf("CreateGroup(%q) succeeded, want an error", name)
+		}
+	}
+	if exists, err := d.GroupExists("backend"); err != nil || !exists {
+		t.Errorf("GroupExists(backend) = %t, %v", exists, err)
+	}
+	if exists, err := d.GroupExists("frontend"); err != nil || exists {
+		t.Errorf("GroupExists(frontend) = %t, %v", exists, err)
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "frontend", "web")
+	addGroup(t, d, "backend", "api", "worker")
+	addGroup(t, d, "empty")
+
+	groups, err := d.ListGroups()
+	if err != nil {
+		t.Fatalf("ListGroups: %v", err)
+	}
+	want := []struct {
+		name  string
+		repos int
+	}{{"backend", 2}, {"empty", 0}, {"frontend", 1}}
+	if len(groups) != len(want) {
+		t.Fatalf("groups = %+v", groups)
+	}
+	for i, g := range groups {
+		if g.Name != want[i].name || g.RepositoryCount != want[i].repos {
+			t.Errorf("group %d = %s with %d repositories, want %s with %d", i, g.Name, g.RepositoryCount, want[i].name, want[i].repos)
+		}
+		if g.CreatedAt.IsZero() {
+			t.Errorf("%s has no creation time", g.Name)
+		}
+	}
+}
+
+func TestAddRepository(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api")
+	addGroup(t, d, "everything")
+
+	tests := []struct {
+		name    string
+		group   string
+		repo    models.GroupRepository
+		wantErr error
+	}{
+		{"same id", "backend", models.GroupRepository{RepositoryID: "api", Name: "api2"}, ErrRepositoryExists},
+		{"same name", "backend", models.GroupRepository{RepositoryID: "other", Name: "api"}, ErrRepositoryExists},
+		{"missing group", "frontend", models.GroupRepository{RepositoryID: "web", Name: "web"}, ErrGroupNotFound},
+		{"another group", "everything", models.GroupRepository{RepositoryID: "api", Name: "backend-api"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := d.AddRepository(tt.group, tt.repo); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The repository is shared, its metadata is not overwritten.
+	repos, err := d.GroupRepositories("everything")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Name != "backend-api" || repos[0].RemoteURL != "https://example.com/api.git" || !repos[0].PendingClone() {
+		t.Errorf("group repositories = %+v", repos)
+	}
+}
+
+func TestRemoveRepository(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api", "worker")
+	addGroup(t, d, "everything", "api")
+
+	if err := d.RemoveRepository("backend", "api"); err != nil {
+		t.Fatalf("RemoveRepository: %v", err)
+	}
+	if err := d.RemoveRepository("backend", "api"); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("removing it again: err = %v, want %v", err, ErrRepositoryNotFound)
+	}
+	if err := d.RemoveRepository("frontend", "api"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("missing group: err = %v, want %v", err, ErrGroupNotFound)
+	}
+
+	for group, want := range map[string][]string{"backend": {"worker"}, "everything": {"api"}} {
+		repos, err := d.GroupRepositories(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		if len(names) != len(want) || names[0] != want[0] {
+			t.Errorf("%s repositories = %v, want %v", group, names, want)
+		}
+	}
+}
+
+func TestSetRepositoryPath(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api")
+
+	if err := d.SetRepositoryPath("backend", "api", "/work/backend/api"); err != nil {
+		t.Fatalf("SetRepositoryPath: %v", err)
+	}
+	if err := d.SetRepositoryPath("backend", "web", "/work/backend/web"); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("missing repository: err = %v, want %v", err, ErrRepositoryNotFound)
+	}
+	repos, err := d.GroupRepositories("backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Path != "/work/backend/api" || repos[0].PendingClone() {
+		t.Errorf("group repositories = %+v", repos)
+	}
+}
+
+func TestDeleteGroup(t *testing.T) {
+	d := newDatabase(t)
+	addGroup(t, d, "backend", "api")
+	addGroup(t, d, "everything", "api")
+
+	if err := d.DeleteGroup("backend"); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+	if err := d.DeleteGroup("backend"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("deleting it again: err = %v, want %v", err, ErrGroupNotFound)
+	}
+	if _, err := d.GroupRepositories("backend"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("GroupRepositories: err = %v, want %v", err, ErrGroupNotFound)
+	}
+	// The memberships go, the repositories stay known.
+	var memberships int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM group_repositories`).Scan(&memberships); err != nil {
+		t.Fatal(err)
+	}
+	if memberships != 1 {
+		t.Errorf("memberships = %d, want 1", memberships)
+	}
+	if repos, err := d.GetRepositories(); err != nil || len(repos) != 1 {
+		t.Errorf("repositories = %+v, %v", repos, err)
+	}
+}
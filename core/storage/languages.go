@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Language sources other than provider names.
+const (
+	// LanguageSourceScan marks languages detected by scanning a working tree.
+	LanguageSourceScan = "scan"
+)
+
+// SaveLanguages replaces the languages recorded for a repository. source names
+// where the numbers come from: a provider such as github, or
+// LanguageSourceScan.
+func (d *Database) SaveLanguages(repositoryID, source string, languages map[string]uint64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM repository_languages WHERE repository_id = ?`, repositoryID); err != nil {
+		return fmt.Errorf("error clearing languages of '%s': %w", repositoryID, err)
+	}
+	now := time.Now().Format(time.RFC3339)
+	for language, bytes := range languages {
+		_, err := tx.Exec(`
+		INSERT INTO repository_languages (repository_id, language, bytes, source, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		`, repositoryID, language, int64(bytes), source, now)
+		if err != nil {
+			return fmt.Errorf("error saving languages of '%s': %w", repositoryID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RepositoryLanguages returns the bytes per language recorded for a
+// repository and where they came from. The map is empty when nothing is
+// recorded.
+func (d *Database) RepositoryLanguages(repositoryID string) (map[string]uint64, string, error) {
+	rows, err := d.db.Query(`
+	SELECT language, bytes, source FROM repository_languages WHERE repository_id = ?
+	`, repositoryID)
+	if err != nil {
+		return nil, "", fmt.Errorf("error querying languages: %w", err)
+	}
+	defer rows.Close()
+
+	languages := make(map[string]uint64)
+	var source string
+	for rows.Next() {
+		var language string
+		var bytes int64
+		if err := rows.Scan(&language, &bytes, &source); err != nil {
+			return nil, "", fmt.Errorf("error scanning row: %w", err)
+		}
+		languages[language] = uint64(bytes)
+	}
+	return languages, source, rows.Err()
+}
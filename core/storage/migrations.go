@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when opening a database that was migrated by a
+// newer version of rr than this one.
+var ErrSchemaTooNew = errors.New("database was migrated by a newer version of rr")
+
+// migration moves the schema from version-1 to version. Each migration runs in
+// its own transaction together with recording it in schema_version.
+type migration struct {
+	version     int
+	description string
+	up          func(tx *sql.Tx) error
+}
+
+// migrations lists every schema change in order. Append new migrations to
+// the end and never change one that has been released.
+//
+// The first five reproduce the schema that was built before databases were
+// versioned. They only create what is missing so they also upgrade such
+// unversioned databases, whatever version of rr created them.
+var migrations = []migration{
+	{1, "create repositories and commits", execSQL(`
+	CREATE TABLE IF NOT EXISTS repositories (
+		id TEXT PRIMARY KEY,
+		name TEXT,
+		default_branch TEXT,
+		remote_url TEXT,
+		last_updated DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS commits (
+		id TEXT PRIMARY KEY,
+		repository_id TEXT,
+		date DATETIME NOT NULL,
+		FOREIGN KEY(repository_id) REFERENCES repositories(id)
+	);
+	`)},
+	{2, "create groups", execSQL(`
+	CREATE TABLE IF NOT EXISTS groups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS group_repositories (
+		group_id INTEGER NOT NULL,
+		repository_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		path TEXT NOT NULL DEFAULT '',
+		added_at DATETIME NOT NULL,
+		PRIMARY KEY (group_id, repository_id),
+		UNIQUE (group_id, name),
+		FOREIGN KEY(group_id) REFERENCES groups(id) ON DELETE CASCADE,
+		FOREIGN KEY(repository_id) REFERENCES repositories(id)
+	);
+	`)},
+	{3, "add provider details to repositories", addColumns("repositories", [][2]string{
+		{"project", "TEXT NOT NULL DEFAULT ''"},
+		{"provider", "TEXT NOT NULL DEFAULT ''"},
+		{"provider_id", "TEXT NOT NULL DEFAULT ''"},
+		{"ssh_url", "TEXT NOT NULL DEFAULT ''"},
+		{"archived", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"fork", "BOOLEAN NOT NULL DEFAULT 0"},
+	})},
+	{4, "create repository_languages", execSQL(`
+	CREATE TABLE IF NOT EXISTS repository_languages (
+		repository_id TEXT NOT NULL,
+		language TEXT NOT NULL,
+		bytes INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (repository_id, language),
+		FOREIGN KEY(repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+	);
+	`)},
+	{5, "add commit details and ingested branches", func(tx *sql.Tx) error {
+		err := addColumns("commits", [][2]string{
+			{"sha", "TEXT NOT NULL DEFAULT ''"},
+			{"author", "TEXT NOT NULL DEFAULT ''"},
+			{"author_email", "TEXT NOT NULL DEFAULT ''"},
+			{"subject", "TEXT NOT NULL DEFAULT ''"},
+			{"files_changed", "INTEGER NOT NULL DEFAULT 0"},
+			{"insertions", "INTEGER NOT NULL DEFAULT 0"},
+			{"deletions", "INTEGER NOT NULL DEFAULT 0"},
+		})(tx)
+		if err != nil {
+			return err
+		}
+		return execSQL(`
+		CREATE TABLE IF NOT EXISTS commit_files (
+			commit_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			insertions INTEGER NOT NULL,
+			deletions INTEGER NOT NULL,
+			PRIMARY KEY (commit_id, path),
+			FOREIGN KEY(commit_id) REFERENCES commits(id) ON DELETE CASCADE
+		);
+		CREATE TABLE IF NOT EXISTS ingested_branches (
+			repository_id TEXT NOT NULL,
+			branch TEXT NOT NULL,
+			sha TEXT NOT NULL,
+			ingested_at DATETIME NOT NULL,
+			PRIMARY KEY (repository_id, branch),
+			FOREIGN KEY(repository_id) REFERENCES repositories(id) ON DELETE CASCADE
+		);
+		`)(tx)
+	}},
+	{6, "add disabled to repositories", addColumns("repositories", [][2]string{
+		{"disabled", "BOOLEAN NOT NULL DEFAULT 0"},
+	})},
+}
+
+// LatestSchemaVersion is the schema version this binary migrates databases to.
+var LatestSchemaVersion = migrations[len(migrations)-1].version
+
+// Migration is a migration known to this binary or recorded in a database.
+type Migration struct {
+	Version     int
+	Description string
+	// AppliedAt is zero while the migration is pending.
+	AppliedAt time.Time
+}
+
+// SchemaStatus is the migration state of a database.
+type SchemaStatus struct {
+	// Version is the schema version of the database, 0 for a database that
+	// doesn't exist yet or predates versioning.
+	Version    int
+	Migrations []Migration
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (s *SchemaStatus) Pending() []Migration {
+	var pending []Migration
+	for _, m := range s.Migrations {
+		if m.AppliedAt.IsZero() {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func execSQL(statements string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+// addColumns adds the given name and definition pairs to table, skipping
+// columns that are already there.
+func addColumns(table string, columns [][2]string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		existing, err := tableColumns(tx, table)
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if existing[column[0]] {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column[0], column[1])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func tableColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// migrate brings the schema of db up to LatestSchemaVersion.
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`); err != nil {
+		return err
+	}
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > LatestSchemaVersion {
+		return fmt.Errorf("%w (schema version %d, this rr supports up to %d); upgrade rr", ErrSchemaTooNew, current, LatestSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs m in its own transaction. Another process may have
+// applied it since the version was read, so the version is read again once
+// the transaction holds the write lock.
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := schemaVersion(tx)
+	if err != nil {
+		return err
+	}
+	if current >= m.version {
+		return nil
+	}
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO schema_version (version, description, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.description, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func schemaVersion(q querier) (int, error) {
+	var version int
+	if err := q.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+	return version, nil
+}
+
+// ReadSchemaStatus reports the migration state of the database at dbPath
+// without migrating it, so it also works for databases newer than this
+// binary. A missing database is reported with every migration pending.
+func ReadSchemaStatus(dbPath string) (*SchemaStatus, error) {
+	applied := make(map[int]Migration)
+	if _, err := os.Stat(dbPath); err == nil {
+		db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+		if err != nil {
+			return nil, fmt.Errorf("error opening database: %w", err)
+		}
+		defer db.Close()
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	status := &SchemaStatus{}
+	for _, m := range migrations {
+		known := Migration{Version: m.version, Description: m.description}
+		if a, ok := applied[m.version]; ok {
+			known.AppliedAt = a.AppliedAt
+			delete(applied, m.version)
+		}
+		status.Migrations = append(status.Migrations, known)
+	}
+	// Migrations of a newer binary are listed as recorded.
+	var newer []Migration
+	for _, m := range applied {
+		newer = append(newer, m)
+	}
+	sort.Slice(newer, func(i, j int) bool { return newer[i].Version < newer[j].Version })
+	status.Migrations = append(status.Migrations, newer...)
+	for _, m := range status.Migrations {
+		if !m.AppliedAt.IsZero() && m.Version > status.Version {
+			status.Version = m.Version
+		}
+	}
+	return status, nil
+}
+
+// appliedMigrations returns the migrations recorded in schema_version by
+// version. It is empty for databases that predate versioning.
+func appliedMigrations(db *sql.DB) (map[int]Migration, error) {
+	applied := make(map[int]Migration)
+	var exists int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`).Scan(&exists)
+	if err != nil || exists == 0 {
+		return applied, err
+	}
+
+	rows, err := db.Query(`SELECT version, description, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema version: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m Migration
+		var appliedAt string
+		if err := rows.Scan(&m.Version, &m.Description, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if m.AppliedAt, err = time.Parse(time.RFC3339, appliedAt); err != nil {
+			return nil, fmt.Errorf("error parsing migration date: %w", err)
+		}
+		applied[m.Version] = m
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// fixtureDatabase creates a database in a temporary directory from the SQL
+// file testdata/name.
+func fixtureDatabase(t *testing.T, name string) string {
+	t.Helper()
+	statements, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "rover.sqlite")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(string(statements)); err != nil {
+		t.Fatalf("loading %s: %v", name, err)
+	}
+	return path
+}
+
+func TestOpenMigratesOldDatabases(t *testing.T) {
+	for _, fixture := range []string{"unversioned.sql", "v3.sql"} {
+		t.Run(fixture, func(t *testing.T) {
+			path := fixtureDatabase(t, fixture)
+
+			d, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer d.Close()
+
+			version, err := schemaVersion(d.db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != LatestSchemaVersion {
+				t.Errorf("schema version = %d, want %d", version, LatestSchemaVersion)
+			}
+
+			// Existing rows survive the migration.
+			repos, err := d.GroupRepositories("backend")
+			if err != nil {
+				t.Fatalf("GroupRepositories: %v", err)
+			}
+			if len(repos) != 2 || repos[0].Name != "api" || repos[0].Path != "/work/backend/api" || !repos[1].PendingClone() {
+				t.Errorf("group repositories = %+v", repos)
+			}
+			all, err := d.GetRepositories()
+			if err != nil {
+				t.Fatalf("GetRepositories: %v", err)
+			}
+			if len(all) != 2 || all[0].Project != "acme" || all[0].DefaultBranch != "main" {
+				t.Errorf("repositories = %+v", all)
+			}
+
+			// Tables and columns added by later migrations are usable.
+			commit := models.Commit{
+				SHA:         "2222222",
+				Author:      "Alice",
+				AuthorEmail: "alice@example.com",
+				Date:        time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC),
+				Subject:     "Add endpoint",
+				Files:       []models.CommitFile{{Path: "main.go", Insertions: 3, Deletions: 1}},
+			}
+			if _, err := d.SaveCommits("example.com/acme/api", "main", commit.SHA, []models.Commit{commit}); err != nil {
+				t.Fatalf("SaveCommits: %v", err)
+			}
+			if err := d.SaveLanguages("example.com/acme/api", LanguageSourceScan, map[string]uint64{"Go": 10}); err != nil {
+				t.Fatalf("SaveLanguages: %v", err)
+			}
+			active, err := d.MostActiveRepositories(CommitFilter{Group: "backend"})
+			if err != nil {
+				t.Fatalf("MostActiveRepositories: %v", err)
+			}
+			if len(active) != 1 || active[0].ActivityCount != 2 {
+				t.Errorf("most active repositories = %+v", active)
+			}
+
+			// Opening a migrated database again applies nothing.
+			d.Close()
+			d, err = Open(path)
+			if err != nil {
+				t.Fatalf("reopening: %v", err)
+			}
+			d.Close()
+			status, err := ReadSchemaStatus(path)
+			if err != nil {
+				t.Fatalf("ReadSchemaStatus: %v", err)
+			}
+			if status.Version != LatestSchemaVersion || len(status.Pending()) != 0 || len(status.Migrations) != len(migrations) {
+				t.Errorf("status after migrating = %+v", status)
+			}
+		})
+	}
+}
+
+func TestReadSchemaStatusDoesNotMigrate(t *testing.T) {
+	path := fixtureDatabase(t, "v3.sql")
+
+	status, err := ReadSchemaStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Version != 3 {
+		t.Errorf("version = %d, want 3", status.Version)
+	}
+	if pending := status.Pending(); len(pending) != LatestSchemaVersion-3 || pending[0].Version != 4 {
+		t.Errorf("pending = %+v", pending)
+	}
+
+	if status, err = ReadSchemaStatus(path); err != nil || status.Version != 3 {
+		t.Errorf("reading the status again = %+v, %v; want version 3", status, err)
+	}
+}
+
+func TestOpenRefusesNewerSchema(t *testing.T) {
+	path := fixtureDatabase(t, "v3.sql")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO schema_version VALUES (?, 'from the future', '2030-01-01T00:00:00Z')`, LatestSchemaVersion+1)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("Open = %v, want ErrSchemaTooNew", err)
+	}
+	status, err := ReadSchemaStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Version != LatestSchemaVersion+1 {
+		t.Errorf("version = %d, want %d", status.Version, LatestSchemaVersion+1)
+	}
+	// The newer database is left untouched.
+	if pending := status.Pending(); len(pending) != LatestSchemaVersion-3 {
+		t.Errorf("pending = %+v", pending)
+	}
+}
+
+func TestOpenConcurrently(t *testing.T) {
+	paths := map[string]string{
+		"new":         filepath.Join(t.TempDir(), "rover.sqlite"),
+		"unversioned": fixtureDatabase(t, "unversioned.sql"),
+	}
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			const opens = 8
+			var wg sync.WaitGroup
+			errs := make(chan error, opens)
+			for i := 0; i < opens; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					d, err := Open(path)
+					if err != nil {
+						errs <- err
+						return
+					}
+					errs <- d.Close()
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("Open: %v", err)
+				}
+			}
+
+			status, err := ReadSchemaStatus(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Version != LatestSchemaVersion || len(status.Pending()) != 0 {
+				t.Errorf("status = %+v, want every migration applied once", status)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/msetsma/RepoRover/core/models"
+	"github.com/msetsma/RepoRover/core/paths"
+
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
@@ -17,6 +20,9 @@ type Database struct {
 	once sync.Once
 }
 
+// DefaultDatabaseName is the database used by the CLI.
+const DefaultDatabaseName = "rover"
+
 var (
 	instances     = make(map[string]*Database) // Map to manage multiple databases
 	instancesLock sync.Mutex                   // Protects the instances map
@@ -33,9 +39,8 @@ func GetDatabaseInstance(dbName string) (*Database, error) {
 	}
 
 	// Create a new instance
-	instance := &Database{}
-	dbPath := getDatabasePath(dbName)
-	if err := instance.initDB(dbPath); err != nil {
+	instance, err := Open(DatabasePath(dbName))
+	if err != nil {
 		return nil, err
 	}
 
@@ -44,14 +49,19 @@ func GetDatabaseInstance(dbName string) (*Database, error) {
 	return instance, nil
 }
 
-// getDatabasePath determines the path to the SQLite database for the given dbName
-func getDatabasePath(dbName string) string {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		configDir = "./" // Fallback to current directory
+// Open opens the SQLite database at dbPath, creating it if needed. Unlike
+// GetDatabaseInstance the returned connection is not shared.
+func Open(dbPath string) (*Database, error) {
+	instance := &Database{}
+	if err := instance.initDB(dbPath); err != nil {
+		return nil, err
 	}
-	dbDir := filepath.Join(configDir, ".reporover", "db")
-	return filepath.Join(dbDir, dbName+".sqlite")
+	return instance, nil
+}
+
+// DatabasePath determines the path to the SQLite database for the given dbName
+func DatabasePath(dbName string) string {
+	return paths.Resolve().DatabaseFile(dbName).Path
 }
 
 // initDB initializes the SQLite database
@@ -61,8 +71,10 @@ func (d *Database) initDB(dbPath string) error {
 		return fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open the database connection
-	db, err := sql.Open("sqlite3", dbPath)
+	// Open the database connection. Write transactions take the lock up front
+	// and wait for other processes, such as a concurrent first run migrating
+	// the database, instead of failing with "database is locked".
+	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on&_txlock=immediate&_busy_timeout=5000")
 	if err != nil {
 		return fmt.Errorf("error opening database: %w", err)
 	}
@@ -72,36 +84,15 @@ func (d *Database) initDB(dbPath string) error {
 		return fmt.Errorf("error connecting to database: %w", err)
 	}
 
-	// Initialize schema
-	if err := d.initSchema(db); err != nil {
-		return fmt.Errorf("error initializing schema: %w", err)
+	if err := migrate(db); err != nil {
+		db.Close()
+		return fmt.Errorf("error migrating database: %w", err)
 	}
 
 	d.db = db
 	return nil
 }
 
-// initSchema ensures required tables are created
-func (d *Database) initSchema(db *sql.DB) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS repositories (
-		id TEXT PRIMARY KEY,
-		name TEXT,
-		default_branch TEXT,
-		remote_url TEXT,
-		last_updated DATETIME
-	);
-	CREATE TABLE IF NOT EXISTS commits (
-		id TEXT PRIMARY KEY,
-		repository_id TEXT,
-		date DATETIME NOT NULL,
-		FOREIGN KEY(repository_id) REFERENCES repositories(id)
-	);
-	`
-	_, err := db.Exec(schema)
-	return err
-}
-
 // Close closes a single database connection
 func (d *Database) Close() error {
 	if d.db != nil {
@@ -124,70 +115,70 @@ func CloseAllDatabases() error {
 	return nil
 }
 
-// SaveRepository saves or updates a repository in the database
+// SaveRepository saves or updates a repository in the database. Languages
+// reported with the repository replace the stored ones.
 func (d *Database) SaveRepository(repo *models.Repository) error {
 	query := `
-	INSERT INTO repositories (id, name, default_branch, remote_url, last_updated)
-	VALUES (?, ?, ?, ?, ?)
+	INSERT INTO repositories (id, name, default_branch, remote_url, project, provider, provider_id, ssh_url, archived, fork, disabled, last_updated)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		name=excluded.name,
 		default_branch=excluded.default_branch,
 		remote_url=excluded.remote_url,
+		project=excluded.project,
+		provider=excluded.provider,
+		provider_id=excluded.provider_id,
+		ssh_url=excluded.ssh_url,
+		archived=excluded.archived,
+		fork=excluded.fork,
+		disabled=excluded.disabled,
 		last_updated=excluded.last_updated
 	`
-	_, err := d.db.Exec(query, repo.ID, repo.Name, repo.DefaultBranch, repo.RemoteURL, repo.LastUpdated.Format(time.RFC3339))
-	return err
+	_, err := d.db.Exec(query, repo.ID, repo.Name, repo.DefaultBranch, repo.RemoteURL, repo.Project,
+		repo.Provider, repo.ProviderID, repo.SSHURL, repo.Archived, repo.Fork, repo.Disabled, repo.LastUpdated.Format(time.RFC3339))
+	if err != nil || len(repo.Languages) == 0 {
+		return err
+	}
+	return d.SaveLanguages(repo.ID, repo.Provider, repo.Languages)
 }
 
 // GetRepositories retrieves all repositories from the database
 func (d *Database) GetRepositories() ([]models.Repository, error) {
 	query := `
-	SELECT id, name, default_branch, remote_url, last_updated
+	SELECT ` + repositoryColumns + `
 	FROM repositories
 	`
-	rows, err := d.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("error querying repositories: %w", err)
-	}
-	defer rows.Close()
-
-	var repos []models.Repository
-	for rows.Next() {
-		var repo models.Repository
-		var lastUpdated string
-		if err := rows.Scan(&repo.ID, &repo.Name, &repo.DefaultBranch, &repo.RemoteURL, &lastUpdated); err != nil {
-			return nil, fmt.Errorf("error scanning row: %w", err)
-		}
-		repo.LastUpdated, err = time.Parse(time.RFC3339, lastUpdated)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing last updated timestamp: %w", err)
-		}
-		repos = append(repos, repo)
-	}
-	return repos, nil
+	return d.queryRepositories(query)
 }
 
-// GetStaleRepositories retrieves repositories not updated for 6 months
-func (d *Database) GetStaleRepositories() ([]models.Repository, error) {
-	query := `
-	SELECT id, name, default_branch, remote_url, last_updated
-	FROM repositories
-	WHERE last_updated < date('now', '-6 months')
-	`
-	return d.queryRepositories(query)
+// GetStaleRepositories retrieves repositories without commits for 6 months
+func (d *Database) GetStaleRepositories() ([]models.ActiveRepository, error) {
+	return d.StaleRepositories("", time.Now().AddDate(0, -6, 0))
 }
 
 // GetMostActiveRepositories retrieves repositories with the most commits in the last 30 days
 func (d *Database) GetMostActiveRepositories() ([]models.ActiveRepository, error) {
+	return d.MostActiveRepositories(CommitFilter{Since: time.Now().AddDate(0, 0, -30)})
+}
+
+// MostActiveRepositories retrieves the repositories with commits matching f,
+// most commits first.
+func (d *Database) MostActiveRepositories(f CommitFilter) ([]models.ActiveRepository, error) {
+	where, args, err := f.where(d)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-	SELECT r.id, r.name, COUNT(c.id) as activity_count
+	SELECT r.id, ` + f.nameColumn() + `, COUNT(c.id) as activity_count,
+		COALESCE(SUM(c.insertions), 0), COALESCE(SUM(c.deletions), 0), MAX(c.date)
 	FROM commits c
 	JOIN repositories r ON c.repository_id = r.id
-	WHERE c.date >= date('now', '-30 days')
-	GROUP BY r.id, r.name
-	ORDER BY activity_count DESC
+	` + f.join() + `
+	WHERE ` + where + `
+	GROUP BY r.id
+	ORDER BY activity_count DESC, 2
 	`
-	rows, err := d.db.Query(query)
+	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying most active repositories: %w", err)
 	}
@@ -196,14 +187,21 @@ func (d *Database) GetMostActiveRepositories() ([]models.ActiveRepository, error
 	var activeRepos []models.ActiveRepository
 	for rows.Next() {
 		var repo models.ActiveRepository
-		if err := rows.Scan(&repo.ID, &repo.Name, &repo.ActivityCount); err != nil {
+		var lastCommit string
+		if err := rows.Scan(&repo.ID, &repo.Name, &repo.ActivityCount, &repo.Insertions, &repo.Deletions, &lastCommit); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
+		if repo.LastCommit, err = time.Parse(time.RFC3339, lastCommit); err != nil {
+			return nil, fmt.Errorf("error parsing commit date: %w", err)
+		}
 		activeRepos = append(activeRepos, repo)
 	}
-	return activeRepos, nil
+	return activeRepos, rows.Err()
 }
 
+// repositoryColumns are the columns queryRepositories scans, in order.
+const repositoryColumns = `id, name, default_branch, remote_url, project, provider, provider_id, ssh_url, archived, fork, disabled, last_updated`
+
 // queryRepositories is a helper for repository queries
 func (d *Database) queryRepositories(query string) ([]models.Repository, error) {
 	rows, err := d.db.Query(query)
@@ -215,10 +213,14 @@ func (d *Database) queryRepositories(query string) ([]models.Repository, error)
 	var repos []models.Repository
 	for rows.Next() {
 		var repo models.Repository
+		var defaultBranch, remoteURL sql.NullString
 		var lastUpdated string
-		if err := rows.Scan(&repo.ID, &repo.Name, &repo.DefaultBranch, &repo.RemoteURL, &lastUpdated); err != nil {
+		if err := rows.Scan(&repo.ID, &repo.Name, &defaultBranch, &remoteURL, &repo.Project,
+			&repo.Provider, &repo.ProviderID, &repo.SSHURL, &repo.Archived, &repo.Fork, &repo.Disabled, &lastUpdated); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
+		repo.DefaultBranch = defaultBranch.String
+		repo.RemoteURL = remoteURL.String
 		repo.LastUpdated, err = time.Parse(time.RFC3339, lastUpdated)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing last updated timestamp: %w", err)
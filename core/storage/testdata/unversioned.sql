@@ -0,0 +1,43 @@
+-- A database written before schemas were versioned, by a build that had
+-- groups and the project column but no provider details, languages or commit
+-- details.
+CREATE TABLE repositories (
+	id TEXT PRIMARY KEY,
+	name TEXT,
+	default_branch TEXT,
+	remote_url TEXT,
+	last_updated DATETIME,
+	project TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE commits (
+	id TEXT PRIMARY KEY,
+	repository_id TEXT,
+	date DATETIME NOT NULL,
+	FOREIGN KEY(repository_id) REFERENCES repositories(id)
+);
+CREATE TABLE groups (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	created_at DATETIME NOT NULL
+);
+CREATE TABLE group_repositories (
+	group_id INTEGER NOT NULL,
+	repository_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	path TEXT NOT NULL DEFAULT '',
+	added_at DATETIME NOT NULL,
+	PRIMARY KEY (group_id, repository_id),
+	UNIQUE (group_id, name),
+	FOREIGN KEY(group_id) REFERENCES groups(id) ON DELETE CASCADE,
+	FOREIGN KEY(repository_id) REFERENCES repositories(id)
+);
+
+INSERT INTO repositories VALUES
+	('example.com/acme/api', 'api', 'main', 'https://example.com/acme/api.git', '2024-01-02T03:04:05Z', 'acme'),
+	('example.com/acme/web', 'web', 'develop', 'https://example.com/acme/web.git', '2024-01-02T03:04:05Z', 'acme');
+INSERT INTO commits VALUES
+	('example.com/acme/api@1111111', 'example.com/acme/api', '2024-01-01T12:00:00Z');
+INSERT INTO groups VALUES (1, 'backend', '2024-01-02T03:04:05Z');
+INSERT INTO group_repositories VALUES
+	(1, 'example.com/acme/api', 'api', '/work/backend/api', '2024-01-02T03:04:05Z'),
+	(1, 'example.com/acme/web', 'web', '', '2024-01-02T03:04:05Z');
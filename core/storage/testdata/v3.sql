@@ -0,0 +1,56 @@
+-- A database migrated to schema version 3.
+CREATE TABLE schema_version (
+	version INTEGER PRIMARY KEY,
+	description TEXT NOT NULL,
+	applied_at DATETIME NOT NULL
+);
+INSERT INTO schema_version VALUES
+	(1, 'create repositories and commits', '2024-02-01T00:00:00Z'),
+	(2, 'create groups', '2024-02-01T00:00:00Z'),
+	(3, 'add provider details to repositories', '2024-02-01T00:00:00Z');
+
+CREATE TABLE repositories (
+	id TEXT PRIMARY KEY,
+	name TEXT,
+	default_branch TEXT,
+	remote_url TEXT,
+	last_updated DATETIME,
+	project TEXT NOT NULL DEFAULT '',
+	provider TEXT NOT NULL DEFAULT '',
+	provider_id TEXT NOT NULL DEFAULT '',
+	ssh_url TEXT NOT NULL DEFAULT '',
+	archived BOOLEAN NOT NULL DEFAULT 0,
+	fork BOOLEAN NOT NULL DEFAULT 0
+);
+CREATE TABLE commits (
+	id TEXT PRIMARY KEY,
+	repository_id TEXT,
+	date DATETIME NOT NULL,
+	FOREIGN KEY(repository_id) REFERENCES repositories(id)
+);
+CREATE TABLE groups (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	created_at DATETIME NOT NULL
+);
+CREATE TABLE group_repositories (
+	group_id INTEGER NOT NULL,
+	repository_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	path TEXT NOT NULL DEFAULT '',
+	added_at DATETIME NOT NULL,
+	PRIMARY KEY (group_id, repository_id),
+	UNIQUE (group_id, name),
+	FOREIGN KEY(group_id) REFERENCES groups(id) ON DELETE CASCADE,
+	FOREIGN KEY(repository_id) REFERENCES repositories(id)
+);
+
+INSERT INTO repositories VALUES
+	('example.com/acme/api', 'api', 'main', 'https://example.com/acme/api.git', '2024-01-02T03:04:05Z', 'acme', 'github', '101', 'git@example.com:acme/api.git', 0, 0),
+	('example.com/acme/web', 'web', 'develop', 'https://example.com/acme/web.git', '2024-01-02T03:04:05Z', 'acme', 'github', '102', 'git@example.com:acme/web.git', 1, 0);
+INSERT INTO commits VALUES
+	('example.com/acme/api@1111111', 'example.com/acme/api', '2024-01-01T12:00:00Z');
+INSERT INTO groups VALUES (1, 'backend', '2024-01-02T03:04:05Z');
+INSERT INTO group_repositories VALUES
+	(1, 'example.com/acme/api', 'api', '/work/backend/api', '2024-01-02T03:04:05Z'),
+	(1, 'example.com/acme/web', 'web', '', '2024-01-02T03:04:05Z');
@@ -0,0 +1,200 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrTemplateNotFound is returned when no template with the given name exists.
+var ErrTemplateNotFound = errors.New("template not found")
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Template describes how to bootstrap a group: the repositories it contains
+// and the group-specific settings it uses. URLs may contain text/template
+// placeholders such as {{ .Org }} that are filled in when the template is
+// applied.
+type Template struct {
+	Description  string            `yaml:"description,omitempty"`
+	Repositories []Repository      `yaml:"repositories"`
+	Overrides    map[string]string `yaml:"overrides,omitempty"`
+}
+
+// Repository is a single repository of a template.
+type Repository struct {
+	Name string `yaml:"name,omitempty"`
+	URL  string `yaml:"url"`
+}
+
+// Dir returns the directory templates are stored in.
+func Dir() string {
+	return paths.Resolve().Templates.Path
+}
+
+func path(name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid template name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	return filepath.Join(Dir(), name+".yaml"), nil
+}
+
+// Load reads the named template.
+func Load(name string) (*Template, error) {
+	p, err := path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template '%s': %w", name, err)
+	}
+
+	t := &Template{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to parse template '%s': %w", name, err)
+	}
+	return t, nil
+}
+
+// Save writes t as the named template, replacing any existing one.
+func Save(name string, t *Template) error {
+	p, err := path(name)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create template directory: %w", err)
+	}
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return fmt.Errorf("failed to write template '%s': %w", name, err)
+	}
+	return nil
+}
+
+// List returns the names of all saved templates.
+func List() ([]string, error) {
+	entries, err := os.ReadDir(Dir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
+			names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Render returns a copy of t with the placeholders in repository names and
+// URLs filled from vars. Placeholders without a value are an error.
+func (t *Template) Render(vars map[string]string) (*Template, error) {
+	out := &Template{Description: t.Description, Overrides: t.Overrides}
+	for _, repo := range t.Repositories {
+		url, err := render(repo.URL, vars)
+		if err != nil {
+			return nil, err
+		}
+		name, err := render(repo.Name, vars)
+		if err != nil {
+			return nil, err
+		}
+		out.Repositories = append(out.Repositories, Repository{Name: name, URL: url})
+	}
+	return out, nil
+}
+
+func render(text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid placeholder in %q: %w", text, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("failed to fill in %q; pass the missing value with --var: %w", text, err)
+	}
+	return b.String(), nil
+}
+
+// Parameterize replaces every occurrence of the values of vars in repository
+// names and URLs with the matching placeholder, the reverse of Render. Text
+// is scanned once; where values overlap, the var first in name order wins,
+// so the same template results every time. Braces that would read as a
+// placeholder are escaped.
+func (t *Template) Parameterize(vars map[string]string) {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{"{{", `{{ "{{" }}`}
+	for _, key := range keys {
+		if value := vars[key]; value != "" {
+			pairs = append(pairs, value, "{{ ."+key+" }}")
+		}
+	}
+	r := strings.NewReplacer(pairs...)
+	for i := range t.Repositories {
+		t.Repositories[i].Name = r.Replace(t.Repositories[i].Name)
+		t.Repositories[i].URL = r.Replace(t.Repositories[i].URL)
+	}
+}
+
+// ApplyOverrides sets the overrides of t on o, validating every key.
+func (t *Template) ApplyOverrides(o *config.GroupOverrides) error {
+	for key, value := range t.Overrides {
+		if err := o.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CaptureOverrides returns the keys set on o in the form stored in templates.
+func CaptureOverrides(o config.GroupOverrides) map[string]string {
+	captured := map[string]string{}
+	for _, key := range config.GroupKeys() {
+		value, err := o.Get(key)
+		if err != nil || value == nil {
+			continue
+		}
+		captured[key] = fmt.Sprint(value)
+	}
+	if len(captured) == 0 {
+		return nil
+	}
+	return captured
+}
+
+// Exists reports whether the named template exists.
+func Exists(name string) bool {
+	p, err := path(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err == nil
+}
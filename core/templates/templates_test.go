@@ -0,0 +1,97 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/config"
+)
+
+func TestParameterize(t *testing.T) {
+	vars := map[string]string{"Org": "acme", "Team": "acme-web", "Unused": ""}
+	original := []Repository{
+		{URL: "https://github.com/acme/api.git"},
+		{Name: "acme-api", URL: "git@github.com:acme/acme-api.git"},
+		{Name: "web", URL: "https://github.com/acme-web/site.git"},
+		{Name: "{{ .Org }}", URL: "https://example.com/{{x}}.git"},
+	}
+	want := []Repository{
+		{URL: "https://github.com/{{ .Org }}/api.git"},
+		{Name: "{{ .Org }}-api", URL: "git@github.com:{{ .Org }}/{{ .Org }}-api.git"},
+		// Org comes first and wins over the longer Team.
+		{Name: "web", URL: "https://github.com/{{ .Org }}-web/site.git"},
+		{Name: `{{ "{{" }} .Org }}`, URL: `https://example.com/{{ "{{" }}x}}.git`},
+	}
+
+	tmpl := &Template{Repositories: append([]Repository(nil), original...)}
+	tmpl.Parameterize(vars)
+	if !reflect.DeepEqual(tmpl.Repositories, want) {
+		t.Errorf("parameterized = %+v, want %+v", tmpl.Repositories, want)
+	}
+
+	rendered, err := tmpl.Render(vars)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !reflect.DeepEqual(rendered.Repositories, original) {
+		t.Errorf("rendered = %+v, want the original %+v", rendered.Repositories, original)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl := &Template{
+		Description:  "backend",
+		Repositories: []Repository{{Name: "{{ .Org }}-api", URL: "https://github.com/{{ .Org }}/api.git"}},
+		Overrides:    map[string]string{"default_branch": "develop"},
+	}
+	got, err := tmpl.Render(map[string]string{"Org": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Template{
+		Description:  "backend",
+		Repositories: []Repository{{Name: "acme-api", URL: "https://github.com/acme/api.git"}},
+		Overrides:    map[string]string{"default_branch": "develop"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rendered = %+v, want %+v", got, want)
+	}
+
+	for _, url := range []string{"https://github.com/{{ .Missing }}/api.git", "https://github.com/{{ .Org /api.git"} {
+		tmpl := &Template{Repositories: []Repository{{URL: url}}}
+		if _, err := tmpl.Render(map[string]string{"Org": "acme"}); err == nil {
+			t.Errorf("rendering %s succeeded, want an error", url)
+		}
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	var o config.GroupOverrides
+	for key, value := range map[string]string{"default_branch": "develop", "credentials.timeout": "0"} {
+		if err := o.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Zero values that are set are kept, unset keys are left out.
+	captured := CaptureOverrides(o)
+	want := map[string]string{"default_branch": "develop", "credentials.timeout": "0"}
+	if !reflect.DeepEqual(captured, want) {
+		t.Errorf("captured = %v, want %v", captured, want)
+	}
+
+	var applied config.GroupOverrides
+	if err := (&Template{Overrides: captured}).ApplyOverrides(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, o) {
+		t.Errorf("applied = %+v, want %+v", applied, o)
+	}
+
+	if err := (&Template{Overrides: map[string]string{"concurrency": "many"}}).ApplyOverrides(&applied); err == nil {
+		t.Error("applying an invalid override succeeded")
+	}
+	if CaptureOverrides(config.GroupOverrides{}) != nil {
+		t.Error("captured overrides of a group without any")
+	}
+}
@@ -2,11 +2,13 @@ package util
 
 import (
 	"github.com/msetsma/RepoRover/core/config"
+	"github.com/msetsma/RepoRover/core/storage"
 )
 
 type CmdTool struct {
 	IOStreams *IOStreams
 	Config    func() (*config.Manifest, error)
+	Database  func() (*storage.Database, error)
 }
 
 func NewCmdTool() *CmdTool {
@@ -14,11 +16,16 @@ func NewCmdTool() *CmdTool {
 		return config.Load()
 	}
 
+	db := func() (*storage.Database, error) {
+		return storage.GetDatabaseInstance(storage.DefaultDatabaseName)
+	}
+
 	// At some point we might need to use the cfg to generate the io streams.
 	io := NewIOStreams()
 
 	return &CmdTool{
 		IOStreams: io,
 		Config:    cfg,
+		Database:  db,
 	}
 }
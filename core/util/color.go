@@ -0,0 +1,29 @@
+package util
+
+import (
+	"fmt"
+)
+
+// ColorScheme wraps text in ANSI colors when color output is enabled.
+type ColorScheme struct {
+	enabled bool
+}
+
+// ColorScheme returns a ColorScheme matching the color setting of the streams.
+func (s *IOStreams) ColorScheme() *ColorScheme {
+	return &ColorScheme{enabled: s.ColorEnabled()}
+}
+
+func (c *ColorScheme) paint(code, text string) string {
+	if !c.enabled || text == "" {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, text)
+}
+
+func (c *ColorScheme) Bold(t string) string   { return c.paint("1", t) }
+func (c *ColorScheme) Red(t string) string    { return c.paint("31", t) }
+func (c *ColorScheme) Green(t string) string  { return c.paint("32", t) }
+func (c *ColorScheme) Yellow(t string) string { return c.paint("33", t) }
+func (c *ColorScheme) Cyan(t string) string   { return c.paint("36", t) }
+func (c *ColorScheme) Gray(t string) string   { return c.paint("90", t) }
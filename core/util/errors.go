@@ -62,3 +62,12 @@ func (e NoResultsError) Error() string {
 func NewNoResultsError(message string) NoResultsError {
 	return NoResultsError{message: message}
 }
+
+// ExitError requests a specific exit code without any error messaging.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"github.com/msetsma/RepoRover/core/config"
+	"github.com/spf13/cobra"
+)
+
+// GroupArg returns the group named by the first positional argument, falling
+// back to the active group when no argument was given.
+func GroupArg(args []string, cfg *config.Manifest) (string, error) {
+	if len(args) > 0 && args[0] != "" {
+		return args[0], nil
+	}
+	if cfg.ActiveGroup == "" {
+		return "", FlagErrorf("no group given and no active group set")
+	}
+	return cfg.ActiveGroup, nil
+}
+
+// GroupSettings resolves the effective settings of group, applying the
+// --concurrency flag when the command has one and it was given.
+func GroupSettings(cmd *cobra.Command, cfg *config.Manifest, group string) (*config.Settings, error) {
+	settings := cfg.Resolve(group)
+	if f := cmd.Flags().Lookup("concurrency"); f != nil && f.Changed {
+		n, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 {
+			return nil, FlagErrorf("--concurrency must be at least 1")
+		}
+		if err := settings.Override("concurrency", n); err != nil {
+			return nil, err
+		}
+	}
+	return settings, nil
+}
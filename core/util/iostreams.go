@@ -94,3 +94,8 @@ func (s *IOStreams) RunWithProgress(label string, task func() error) error {
 	defer s.StopProgressIndicator()
 	return task()
 }
+
+// CanPrompt reports whether the user can be asked questions interactively.
+func (s *IOStreams) CanPrompt() bool {
+	return s.stdinIsTTY && s.stdoutIsTTY
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// Confirm asks a yes/no question on the terminal. Anything but an explicit
+// yes is treated as no.
+func Confirm(io *IOStreams, question string) (bool, error) {
+	if !io.CanPrompt() {
+		return false, fmt.Errorf("cannot prompt for confirmation when not running interactively")
+	}
+	fmt.Fprintf(io.Out, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(io.In).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type tableField struct {
+	text  string
+	color func(string) string
+}
+
+// TablePrinter writes rows of fields as aligned columns. Colors are applied
+// after padding so they never affect alignment.
+type TablePrinter struct {
+	out  io.Writer
+	rows [][]tableField
+	row  []tableField
+}
+
+func NewTablePrinter(out io.Writer) *TablePrinter {
+	return &TablePrinter{out: out}
+}
+
+// AddHeader adds a row of column titles.
+func (t *TablePrinter) AddHeader(columns ...string) {
+	for _, c := range columns {
+		t.AddField(strings.ToUpper(c), nil)
+	}
+	t.EndRow()
+}
+
+// AddField adds a field to the current row. color may be nil.
+func (t *TablePrinter) AddField(text string, color func(string) string) {
+	t.row = append(t.row, tableField{text: text, color: color})
+}
+
+// EndRow finishes the current row.
+func (t *TablePrinter) EndRow() {
+	t.rows = append(t.rows, t.row)
+	t.row = nil
+}
+
+// Render writes the table.
+func (t *TablePrinter) Render() error {
+	if len(t.row) > 0 {
+		t.EndRow()
+	}
+
+	var widths []int
+	for _, row := range t.rows {
+		for i, f := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(f.text))
+		}
+	}
+
+	for _, row := range t.rows {
+		var b strings.Builder
+		for i, f := range row {
+			text := f.text
+			if i < len(row)-1 {
+				text += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(f.text)+2)
+			}
+			if f.color != nil {
+				text = f.color(f.text) + text[len(f.text):]
+			}
+			b.WriteString(text)
+		}
+		if _, err := fmt.Fprintln(t.out, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
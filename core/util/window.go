@@ -0,0 +1,31 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WindowStart returns the start of a window such as 30d, 12w, 6m or 1y that
+// ends at now.
+func WindowStart(window string, now time.Time) (time.Time, error) {
+	invalid := fmt.Errorf("invalid window %q: use a number followed by d, w, m or y, e.g. 30d or 6m", window)
+	if len(window) < 2 {
+		return time.Time{}, invalid
+	}
+	n, err := strconv.Atoi(window[:len(window)-1])
+	if err != nil || n < 1 {
+		return time.Time{}, invalid
+	}
+	switch window[len(window)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	}
+	return time.Time{}, invalid
+}
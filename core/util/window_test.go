@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowStart(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		window string
+		want   time.Time
+	}{
+		{"30d", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC)},
+		// Months are calendar months; February 31 normalizes to March 2.
+		{"1m", time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)},
+		{"6m", time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := WindowStart(tt.window, now)
+		if err != nil {
+			t.Errorf("WindowStart(%q): %v", tt.window, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("WindowStart(%q) = %s, want %s", tt.window, got, tt.want)
+		}
+	}
+
+	for _, window := range []string{"", "d", "30", "0d", "-1d", "30h", "1.5m", "m6"} {
+		if _, err := WindowStart(window, now); err == nil {
+			t.Errorf("WindowStart(%q) succeeded, want an error", window)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// CloneStatus describes the outcome of cloning a single repository.
+type CloneStatus string
+
+const (
+	CloneStatusCloned  CloneStatus = "cloned"
+	CloneStatusPresent CloneStatus = "present"
+	CloneStatusFailed  CloneStatus = "failed"
+)
+
+// CloneResult is the outcome of cloning a single repository.
+type CloneResult struct {
+	Repo   models.GroupRepository
+	Dir    string
+	Status CloneStatus
+	Err    error
+}
+
+// Clone clones every repository of the group that is missing on disk, using
+// at most limit concurrent clones. Repositories whose working tree already
+// exists are skipped.
+func (w Workspace) Clone(ctx context.Context, repos []models.GroupRepository, limit int) []CloneResult {
+	return Parallel(ctx, repos, limit, func(ctx context.Context, repo models.GroupRepository) CloneResult {
+		dir := w.RepoDir(repo)
+		result := CloneResult{Repo: repo, Dir: dir}
+
+		if git.IsRepository(ctx, dir) {
+			result.Status = CloneStatusPresent
+			return result
+		}
+
+		if err := cloneInto(ctx, repo, dir, w.Credentials); err != nil {
+			result.Status, result.Err = CloneStatusFailed, err
+			return result
+		}
+		result.Status = CloneStatusCloned
+		return result
+	})
+}
+
+func cloneInto(ctx context.Context, repo models.GroupRepository, dir string, creds git.Credentials) error {
+	if repo.RemoteURL == "" {
+		return fmt.Errorf("%s is missing and has no remote to clone from", dir)
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s exists and is not an empty directory", dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dir), err)
+	}
+	return git.Clone(ctx, repo.RemoteURL, dir, creds)
+}
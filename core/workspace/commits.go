@@ -0,0 +1,120 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// CommitsOptions control which commits Commits reads.
+type CommitsOptions struct {
+	// Branches to read. When empty, the repository's default branch, or
+	// DefaultBranch when the repository has none recorded, is read.
+	Branches []string
+	// AllBranches reads every local branch instead.
+	AllBranches   bool
+	DefaultBranch string
+	// Since holds the last commit read per repository id and branch. Only
+	// commits after it are returned, unless the branch was rewritten.
+	Since map[string]map[string]string
+	Limit int
+}
+
+// CommitsResult holds the commits read from a single repository.
+type CommitsResult struct {
+	Repo     models.GroupRepository
+	Dir      string
+	Branches []BranchCommits
+	// NotCloned is set when there is no working tree to read from.
+	NotCloned bool
+	Err       error
+}
+
+// BranchCommits are the new commits of a branch. Head is the commit the
+// branch pointed at and becomes the starting point of the next read.
+type BranchCommits struct {
+	Branch  string
+	Head    string
+	Commits []models.Commit
+	Err     error
+}
+
+// Commits reads the commits of every repository of the group, using at most
+// opts.Limit concurrent workers.
+func (w Workspace) Commits(ctx context.Context, repos []models.GroupRepository, opts CommitsOptions) []CommitsResult {
+	return Parallel(ctx, repos, opts.Limit, func(ctx context.Context, repo models.GroupRepository) CommitsResult {
+		result := CommitsResult{Repo: repo, Dir: w.RepoDir(repo)}
+		if !git.IsRepository(ctx, result.Dir) {
+			result.NotCloned = true
+			return result
+		}
+
+		branches := opts.Branches
+		switch {
+		case opts.AllBranches:
+			var err error
+			if branches, err = git.LocalBranches(ctx, result.Dir); err != nil {
+				result.Err = err
+				return result
+			}
+		case len(branches) == 0 && repo.DefaultBranch != "":
+			branches = []string{repo.DefaultBranch}
+		case len(branches) == 0:
+			branches = []string{opts.DefaultBranch}
+		}
+
+		for _, branch := range branches {
+			bc := BranchCommits{Branch: branch}
+			bc.Head, bc.Commits, bc.Err = readBranch(ctx, result.Dir, repo.RepositoryID, branch, opts.Since[repo.RepositoryID][branch])
+			result.Branches = append(result.Branches, bc)
+		}
+		return result
+	})
+}
+
+// readBranch returns the head of branch and the commits after since. The
+// local branch is preferred over origin's.
+func readBranch(ctx context.Context, dir, repositoryID, branch, since string) (string, []models.Commit, error) {
+	head, err := git.RevParse(ctx, dir, "refs/heads/"+branch)
+	if err != nil {
+		if head, err = git.RevParse(ctx, dir, "refs/remotes/origin/"+branch); err != nil {
+			return "", nil, fmt.Errorf("branch %s not found", branch)
+		}
+	}
+	if since == head {
+		return head, nil, nil
+	}
+	// A rewritten branch no longer contains the last commit read, so it is
+	// read in full; commits that are already stored are skipped on save.
+	if since != "" {
+		if ok, err := git.IsAncestor(ctx, dir, since, head); err != nil || !ok {
+			since = ""
+		}
+	}
+
+	log, err := git.Log(ctx, dir, head, since)
+	if err != nil {
+		return "", nil, err
+	}
+	commits := make([]models.Commit, 0, len(log))
+	for _, c := range log {
+		commit := models.Commit{
+			SHA:          c.SHA,
+			RepositoryID: repositoryID,
+			Author:       c.Author,
+			AuthorEmail:  c.AuthorEmail,
+			Date:         c.Date,
+			Subject:      c.Subject,
+			FilesChanged: len(c.Files),
+			Insertions:   c.Insertions(),
+			Deletions:    c.Deletions(),
+		}
+		for _, f := range c.Files {
+			commit.Files = append(commit.Files, models.CommitFile{Path: f.Path, Insertions: f.Insertions, Deletions: f.Deletions})
+		}
+		commits = append(commits, commit)
+	}
+	return head, commits, nil
+}
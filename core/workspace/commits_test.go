@@ -0,0 +1,107 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/msetsma/RepoRover/core/git"
+)
+
+// gitRun runs git in dir and fails the test on errors.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git.Run(context.Background(), dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// newRepository creates a repository on main without commits. Git runs
+// without the user's configuration.
+func newRepository(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ada")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ada@example.com")
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "--quiet")
+	gitRun(t, dir, "symbolic-ref", "HEAD", "refs/heads/main")
+	return dir
+}
+
+// commitFile commits a line to file and returns the new HEAD.
+func commitFile(t *testing.T, dir, file, message string) string {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(dir, file), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString(message + "\n")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", file)
+	gitRun(t, dir, "commit", "--quiet", "-m", message)
+	return gitRun(t, dir, "rev-parse", "HEAD")
+}
+
+func TestReadBranch(t *testing.T) {
+	ctx := context.Background()
+	dir := newRepository(t)
+	a := commitFile(t, dir, "README", "a")
+	b := commitFile(t, dir, "main.go", "b")
+
+	read := func(branch, since string) (string, []string) {
+		t.Helper()
+		head, commits, err := readBranch(ctx, dir, "api", branch, since)
+		if err != nil {
+			t.Fatalf("readBranch(%s, %.7s): %v", branch, since, err)
+		}
+		var shas []string
+		for _, c := range commits {
+			if c.RepositoryID != "api" || c.FilesChanged != 1 || c.Insertions != 1 {
+				t.Errorf("commit %.7s = %+v", c.SHA, c)
+			}
+			shas = append(shas, c.SHA)
+		}
+		return head, shas
+	}
+
+	if head, shas := read("main", ""); head != b || !reflect.DeepEqual(shas, []string{b, a}) {
+		t.Errorf("first read: head %.7s, commits %.7q; want %.7s and %.7q", head, shas, b, []string{b, a})
+	}
+
+	c := commitFile(t, dir, "main.go", "c")
+	if head, shas := read("main", b); head != c || !reflect.DeepEqual(shas, []string{c}) {
+		t.Errorf("after %.7s: head %.7s, commits %.7q; want %.7s and only %.7s", b, head, shas, c, c)
+	}
+	if head, shas := read("main", c); head != c || len(shas) != 0 {
+		t.Errorf("nothing new: head %.7s, commits %.7q", head, shas)
+	}
+
+	// c is no longer on the rewritten branch, which is read in full.
+	gitRun(t, dir, "reset", "--quiet", "--hard", a)
+	d := commitFile(t, dir, "main.go", "d")
+	if head, shas := read("main", c); head != d || !reflect.DeepEqual(shas, []string{d, a}) {
+		t.Errorf("rewritten: head %.7s, commits %.7q; want %.7s and %.7q", head, shas, d, []string{d, a})
+	}
+
+	// Branches only origin has are read from the remote-tracking branch.
+	gitRun(t, dir, "update-ref", "refs/remotes/origin/release", b)
+	if head, shas := read("release", a); head != b || !reflect.DeepEqual(shas, []string{b}) {
+		t.Errorf("origin/release: head %.7s, commits %.7q; want %.7s and %.7q", head, shas, b, []string{b})
+	}
+
+	if _, _, err := readBranch(ctx, dir, "api", "missing", ""); err == nil {
+		t.Error("reading a missing branch succeeded")
+	}
+}
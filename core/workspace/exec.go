@@ -0,0 +1,108 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// ExecOptions controls how a command is run across a group.
+type ExecOptions struct {
+	// Limit is the maximum number of repositories the command runs in at once.
+	Limit int
+	// FailFast stops starting new commands, and kills running ones, after the
+	// first failure.
+	FailFast bool
+	// OnDone, if set, is called as soon as the command finished in a
+	// repository. Calls are never concurrent.
+	OnDone func(ExecResult)
+}
+
+// ExecResult is the outcome of running a command in a single repository.
+type ExecResult struct {
+	Repo     models.GroupRepository
+	Dir      string
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	Err      error
+	// Skipped is set when the command never ran, either because the
+	// repository isn't cloned or because an earlier failure cancelled it.
+	Skipped bool
+}
+
+// Failed reports whether the command ran and did not succeed.
+func (r ExecResult) Failed() bool {
+	return !r.Skipped && (r.Err != nil || r.ExitCode != 0)
+}
+
+// Exec runs command in the working tree of every repository. A single
+// argument is interpreted by the shell, so "git fetch --all" works as one
+// quoted string; multiple arguments are executed directly.
+func (w Workspace) Exec(ctx context.Context, repos []models.GroupRepository, command []string, opts ExecOptions) []ExecResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	return Parallel(ctx, repos, opts.Limit, func(ctx context.Context, repo models.GroupRepository) ExecResult {
+		result := ExecResult{Repo: repo, Dir: w.RepoDir(repo)}
+		if ctx.Err() != nil || !git.IsRepository(ctx, result.Dir) {
+			result.Skipped = true
+		} else {
+			run(ctx, command, &result)
+			// Commands killed because another repository failed first are
+			// reported as skipped rather than as failures of their own.
+			if result.Failed() && ctx.Err() != nil {
+				result.Skipped = true
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Failed() && opts.FailFast {
+			cancel()
+		}
+		if opts.OnDone != nil {
+			opts.OnDone(result)
+		}
+		return result
+	})
+}
+
+func run(ctx context.Context, command []string, result *ExecResult) {
+	var cmd *exec.Cmd
+	switch {
+	case len(command) > 1:
+		cmd = exec.CommandContext(ctx, command[0], command[1:]...)
+	case runtime.GOOS == "windows":
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command[0])
+	default:
+		cmd = exec.CommandContext(ctx, "sh", "-c", command[0])
+	}
+	cmd.Dir = result.Dir
+	// Children of a killed shell may keep its output open; don't wait for
+	// them to finish.
+	cmd.WaitDelay = time.Second
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	result.Stdout, result.Stderr = stdout.Bytes(), stderr.Bytes()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode, result.Err = 1, err
+	}
+}
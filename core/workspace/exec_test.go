@@ -0,0 +1,98 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// execRepositories creates a repository for each of names, with a file
+// named fail in those listed in failing.
+func execRepositories(t *testing.T, names []string, failing ...string) []models.GroupRepository {
+	t.Helper()
+	var repos []models.GroupRepository
+	for _, name := range names {
+		dir := newRepository(t)
+		repos = append(repos, models.GroupRepository{RepositoryID: name, Name: name, Path: dir})
+	}
+	for _, name := range failing {
+		for _, repo := range repos {
+			if repo.Name == name {
+				if err := os.WriteFile(filepath.Join(repo.Path, "fail"), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	return repos
+}
+
+func TestExecFailFast(t *testing.T) {
+	repos := execRepositories(t, []string{"a", "b", "c"}, "a")
+	start := time.Now()
+	results := New(t.TempDir(), "g").Exec(context.Background(), repos, []string{"if [ -e fail ]; then sleep 0.5; exit 3; fi; sleep 10"}, ExecOptions{Limit: 3, FailFast: true})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %s, the other commands were not stopped", elapsed)
+	}
+	if !results[0].Failed() || results[0].ExitCode != 3 {
+		t.Errorf("a = %+v, want exit 3", results[0])
+	}
+	for _, r := range results[1:] {
+		if !r.Skipped || r.Failed() {
+			t.Errorf("%s = %+v, want skipped", r.Repo.Name, r)
+		}
+	}
+}
+
+func TestExecSerial(t *testing.T) {
+	repos := execRepositories(t, []string{"a", "b", "c"}, "b")
+	// mkdir fails while another repository holds the lock.
+	lock := filepath.Join(t.TempDir(), "lock")
+	command := `mkdir "` + lock + `" && sleep 0.1 && rmdir "` + lock + `" && [ ! -e fail ]`
+
+	var done []string
+	results := New(t.TempDir(), "g").Exec(context.Background(), repos, []string{command}, ExecOptions{
+		Limit:  1,
+		OnDone: func(r ExecResult) { done = append(done, r.Repo.Name) },
+	})
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(done, want) {
+		t.Errorf("finished in order %v, want %v", done, want)
+	}
+	// Without FailFast, c still runs after b failed.
+	for i, wantFailed := range []bool{false, true, false} {
+		if r := results[i]; r.Skipped || r.Failed() != wantFailed {
+			t.Errorf("%s = %+v, want failed %t", r.Repo.Name, r, wantFailed)
+		}
+	}
+}
+
+func TestExecOutput(t *testing.T) {
+	repos := execRepositories(t, []string{"a"})
+	repos = append(repos, models.GroupRepository{RepositoryID: "missing", Name: "missing", Path: filepath.Join(t.TempDir(), "missing")})
+	ws := New(t.TempDir(), "g")
+	ctx := context.Background()
+
+	results := ws.Exec(ctx, repos, []string{"echo out; echo err >&2; exit 3"}, ExecOptions{Limit: 2})
+	if r := results[0]; string(r.Stdout) != "out\n" || string(r.Stderr) != "err\n" || r.ExitCode != 3 || r.Err != nil {
+		t.Errorf("shell command = %+v", r)
+	}
+	if r := results[1]; !r.Skipped {
+		t.Errorf("not cloned = %+v, want skipped", r)
+	}
+
+	// Multiple arguments are run directly, without a shell.
+	results = ws.Exec(ctx, repos[:1], []string{"printf", "%s|", "a b", "$HOME"}, ExecOptions{Limit: 1})
+	if r := results[0]; r.Failed() || string(r.Stdout) != "a b|$HOME|" {
+		t.Errorf("direct command printed %q, want the arguments as they are", r.Stdout)
+	}
+
+	results = ws.Exec(ctx, repos[:1], []string{"rr-no-such-command", "x"}, ExecOptions{Limit: 1})
+	if r := results[0]; !r.Failed() || r.Err == nil {
+		t.Errorf("missing command = %+v, want an error", r)
+	}
+}
@@ -0,0 +1,119 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// PullOutcome describes what happened when pulling a single repository.
+type PullOutcome string
+
+const (
+	PullUpdated    PullOutcome = "updated"
+	PullUpToDate   PullOutcome = "up to date"
+	PullAhead      PullOutcome = "ahead"
+	PullNotCloned  PullOutcome = "not cloned"
+	PullDirty      PullOutcome = "dirty"
+	PullDiverged   PullOutcome = "diverged"
+	PullNoUpstream PullOutcome = "no upstream"
+	PullDetached   PullOutcome = "detached"
+	PullFailed     PullOutcome = "failed"
+)
+
+// Failed reports whether the outcome should be treated as an error.
+func (o PullOutcome) Failed() bool {
+	switch o {
+	case PullUpdated, PullUpToDate, PullAhead, PullNotCloned:
+		return false
+	}
+	return true
+}
+
+// PullResult is the outcome of pulling a single repository.
+type PullResult struct {
+	Repo    models.GroupRepository
+	Dir     string
+	Branch  string
+	OldSHA  string
+	NewSHA  string
+	Outcome PullOutcome
+	Err     error
+}
+
+// Pull fetches every repository of the group and fast-forwards its current
+// branch to its upstream, using at most limit concurrent workers. Nothing is
+// merged when the working tree is dirty or the branch has diverged.
+func (w Workspace) Pull(ctx context.Context, repos []models.GroupRepository, limit int) []PullResult {
+	return Parallel(ctx, repos, limit, func(ctx context.Context, repo models.GroupRepository) PullResult {
+		result := PullResult{Repo: repo, Dir: w.RepoDir(repo)}
+		result.Outcome, result.Err = pull(ctx, &result, w.Credentials)
+		return result
+	})
+}
+
+func pull(ctx context.Context, r *PullResult, creds git.Credentials) (PullOutcome, error) {
+	if !git.IsRepository(ctx, r.Dir) {
+		return PullNotCloned, nil
+	}
+
+	branch, err := git.CurrentBranch(ctx, r.Dir)
+	if err != nil {
+		return PullFailed, err
+	}
+	if branch == "" {
+		return PullDetached, fmt.Errorf("HEAD is detached")
+	}
+	r.Branch = branch
+
+	if r.OldSHA, err = git.RevParse(ctx, r.Dir, "HEAD"); err != nil {
+		return PullFailed, err
+	}
+	r.NewSHA = r.OldSHA
+
+	dirty, err := git.IsDirty(ctx, r.Dir)
+	if err != nil {
+		return PullFailed, err
+	}
+	if dirty {
+		return PullDirty, fmt.Errorf("working tree has uncommitted changes")
+	}
+
+	upstream, err := git.Upstream(ctx, r.Dir)
+	if err != nil {
+		return PullFailed, err
+	}
+	if upstream == "" {
+		return PullNoUpstream, fmt.Errorf("branch %s has no upstream", branch)
+	}
+
+	if err := git.Fetch(ctx, r.Dir, creds); err != nil {
+		return PullFailed, err
+	}
+	remote, err := git.RevParse(ctx, r.Dir, upstream)
+	if err != nil {
+		return PullFailed, err
+	}
+	if remote == r.OldSHA {
+		return PullUpToDate, nil
+	}
+
+	if ok, err := git.IsAncestor(ctx, r.Dir, r.OldSHA, remote); err != nil {
+		return PullFailed, err
+	} else if !ok {
+		if ahead, err := git.IsAncestor(ctx, r.Dir, remote, r.OldSHA); err != nil {
+			return PullFailed, err
+		} else if ahead {
+			return PullAhead, nil
+		}
+		return PullDiverged, fmt.Errorf("%s has diverged from %s", branch, upstream)
+	}
+
+	if err := git.MergeFastForward(ctx, r.Dir, remote); err != nil {
+		return PullFailed, err
+	}
+	r.NewSHA = remote
+	return PullUpdated, nil
+}
@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// scpLikeURL matches remotes such as git@github.com:user/repo.git.
+var scpLikeURL = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):([^/].*)$`)
+
+// IsRemoteURL reports whether s looks like a clone URL rather than a path.
+func IsRemoteURL(s string) bool {
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host+u.Path != "" && len(u.Scheme) > 1 {
+		return true
+	}
+	return !filepath.IsAbs(s) && scpLikeURL.MatchString(s)
+}
+
+// ExpandHome replaces a leading ~ with the current user's home directory.
+func ExpandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, `~\`) {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, p[1:]), nil
+}
+
+// RepositoryID derives a stable identifier from a clone URL so the same
+// repository is recognised regardless of protocol, e.g. both
+// https://github.com/user/repo.git and git@github.com:user/repo map to
+// github.com/user/repo.
+func RepositoryID(remote string) string {
+	remote = strings.TrimSpace(remote)
+	var host, p string
+	if m := scpLikeURL.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		host, p = m[1], m[2]
+	} else if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Scheme != "file" {
+		host, p = u.Hostname(), u.Path
+	} else {
+		p = strings.TrimPrefix(remote, "file://")
+		if abs, err := filepath.Abs(p); err == nil {
+			p = filepath.ToSlash(abs)
+		}
+		return "file://" + strings.TrimSuffix(strings.TrimRight(p, "/"), ".git")
+	}
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	return strings.ToLower(host) + "/" + p
+}
+
+// RepositoryName returns the last path element of a clone URL without the
+// .git suffix.
+func RepositoryName(remote string) string {
+	return path.Base(RepositoryID(remote))
+}
+
+// Resolve turns a clone URL or a path to an existing working tree into a
+// group repository. Local working trees keep their location and are
+// identified by their origin remote when they have one. URLs, and paths to
+// bare repositories, resolve to a repository without a path, i.e. one that
+// is pending clone.
+func Resolve(ctx context.Context, arg string) (models.GroupRepository, error) {
+	if IsRemoteURL(arg) {
+		return remoteRepository(arg), nil
+	}
+
+	dir, err := ExpandHome(arg)
+	if err != nil {
+		return models.GroupRepository{}, err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return models.GroupRepository{}, err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return models.GroupRepository{}, fmt.Errorf("%s is neither a clone URL nor an existing directory", arg)
+	}
+
+	if git.IsBare(ctx, dir) {
+		return remoteRepository(dir), nil
+	}
+
+	top, err := git.TopLevel(ctx, dir)
+	if err != nil {
+		return models.GroupRepository{}, fmt.Errorf("%s is not a git working tree", arg)
+	}
+	top = filepath.Clean(top)
+
+	remote, err := git.RemoteURL(ctx, top, "origin")
+	if err != nil {
+		return models.GroupRepository{}, err
+	}
+
+	repo := models.GroupRepository{
+		Name:      filepath.Base(top),
+		RemoteURL: remote,
+		Path:      top,
+	}
+	if remote != "" {
+		repo.RepositoryID = RepositoryID(remote)
+	} else {
+		repo.RepositoryID = "file://" + filepath.ToSlash(top)
+	}
+	return repo, nil
+}
+
+func remoteRepository(remote string) models.GroupRepository {
+	return models.GroupRepository{
+		RepositoryID: RepositoryID(remote),
+		Name:         RepositoryName(remote),
+		RemoteURL:    remote,
+	}
+}
@@ -0,0 +1,30 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// StatusResult is the status of a single repository of a group. Status is
+// nil when the repository is not cloned or its status could not be read.
+type StatusResult struct {
+	Repo   models.GroupRepository
+	Dir    string
+	Status *git.Status
+	Err    error
+}
+
+// Status reads the status of every repository of the group, using at most
+// limit concurrent workers.
+func (w Workspace) Status(ctx context.Context, repos []models.GroupRepository, limit int) []StatusResult {
+	return Parallel(ctx, repos, limit, func(ctx context.Context, repo models.GroupRepository) StatusResult {
+		result := StatusResult{Repo: repo, Dir: w.RepoDir(repo)}
+		if !git.IsRepository(ctx, result.Dir) {
+			return result
+		}
+		result.Status, result.Err = git.GetStatus(ctx, result.Dir)
+		return result
+	})
+}
@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// SyncOutcome describes what happened when synchronising a single repository.
+type SyncOutcome string
+
+const (
+	SyncUpdated   SyncOutcome = "updated"
+	SyncUpToDate  SyncOutcome = "up to date"
+	SyncAhead     SyncOutcome = "ahead"
+	SyncCreated   SyncOutcome = "created"
+	SyncNoBranch  SyncOutcome = "no such branch"
+	SyncNotCloned SyncOutcome = "not cloned"
+	SyncDirty     SyncOutcome = "dirty"
+	SyncDiverged  SyncOutcome = "diverged"
+	SyncFailed    SyncOutcome = "failed"
+)
+
+// Failed reports whether the outcome should be treated as an error. A branch
+// that exists nowhere is reported but does not fail the run.
+func (o SyncOutcome) Failed() bool {
+	switch o {
+	case SyncUpdated, SyncUpToDate, SyncAhead, SyncCreated, SyncNoBranch, SyncNotCloned:
+		return false
+	}
+	return true
+}
+
+// SyncResult is the outcome of synchronising a single repository.
+type SyncResult struct {
+	Repo    models.GroupRepository
+	Dir     string
+	Branch  string
+	OldSHA  string
+	NewSHA  string
+	Outcome SyncOutcome
+	Err     error
+}
+
+// Sync fetches every repository of the group, checks out branch and
+// fast-forwards it to origin, using at most limit concurrent workers. When
+// only origin has the branch a local tracking branch is created.
+func (w Workspace) Sync(ctx context.Context, repos []models.GroupRepository, branch string, limit int) []SyncResult {
+	return Parallel(ctx, repos, limit, func(ctx context.Context, repo models.GroupRepository) SyncResult {
+		result := SyncResult{Repo: repo, Dir: w.RepoDir(repo), Branch: branch}
+		result.Outcome, result.Err = syncBranch(ctx, &result, w.Credentials)
+		return result
+	})
+}
+
+func syncBranch(ctx context.Context, r *SyncResult, creds git.Credentials) (SyncOutcome, error) {
+	const remote = "origin"
+
+	if !git.IsRepository(ctx, r.Dir) {
+		return SyncNotCloned, nil
+	}
+	if err := git.FetchAll(ctx, r.Dir, creds); err != nil {
+		return SyncFailed, err
+	}
+
+	local := git.HasRef(ctx, r.Dir, "refs/heads/"+r.Branch)
+	tracked := git.HasRef(ctx, r.Dir, "refs/remotes/"+remote+"/"+r.Branch)
+	if !local && !tracked {
+		return SyncNoBranch, nil
+	}
+
+	current, err := git.CurrentBranch(ctx, r.Dir)
+	if err != nil {
+		return SyncFailed, err
+	}
+	if current != r.Branch {
+		if dirty, err := git.IsDirty(ctx, r.Dir); err != nil {
+			return SyncFailed, err
+		} else if dirty {
+			return SyncDirty, fmt.Errorf("working tree has uncommitted changes on %s", current)
+		}
+	}
+
+	if !local {
+		if err := git.CheckoutTracking(ctx, r.Dir, remote, r.Branch); err != nil {
+			return SyncFailed, err
+		}
+		r.NewSHA, err = git.RevParse(ctx, r.Dir, "HEAD")
+		if err != nil {
+			return SyncFailed, err
+		}
+		return SyncCreated, nil
+	}
+
+	if current != r.Branch {
+		if err := git.Checkout(ctx, r.Dir, r.Branch); err != nil {
+			return SyncFailed, err
+		}
+	}
+	if r.OldSHA, err = git.RevParse(ctx, r.Dir, "HEAD"); err != nil {
+		return SyncFailed, err
+	}
+	r.NewSHA = r.OldSHA
+	if !tracked {
+		return SyncUpToDate, nil
+	}
+
+	target, err := git.RevParse(ctx, r.Dir, remote+"/"+r.Branch)
+	if err != nil {
+		return SyncFailed, err
+	}
+	if target == r.OldSHA {
+		return SyncUpToDate, nil
+	}
+	if ok, err := git.IsAncestor(ctx, r.Dir, r.OldSHA, target); err != nil {
+		return SyncFailed, err
+	} else if !ok {
+		if ahead, err := git.IsAncestor(ctx, r.Dir, target, r.OldSHA); err != nil {
+			return SyncFailed, err
+		} else if ahead {
+			return SyncAhead, nil
+		}
+		return SyncDiverged, fmt.Errorf("%s has diverged from %s/%s", r.Branch, remote, r.Branch)
+	}
+
+	if dirty, err := git.IsDirty(ctx, r.Dir); err != nil {
+		return SyncFailed, err
+	} else if dirty {
+		return SyncDirty, fmt.Errorf("working tree has uncommitted changes")
+	}
+	if err := git.MergeFastForward(ctx, r.Dir, target); err != nil {
+		return SyncFailed, err
+	}
+	r.NewSHA = target
+	return SyncUpdated, nil
+}
@@ -0,0 +1,65 @@
+package workspace
+
+import (
+	"context"
+	"os"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// Problem describes what is wrong with the working tree of a repository.
+type Problem string
+
+const (
+	ProblemNone           Problem = ""
+	ProblemPendingClone   Problem = "pending clone"
+	ProblemMissing        Problem = "missing"
+	ProblemNotRepository  Problem = "not a git repository"
+	ProblemNoRemote       Problem = "no origin remote"
+	ProblemRemoteMismatch Problem = "remote mismatch"
+)
+
+// VerifyResult is the outcome of verifying a single repository. Remote is
+// the URL of its origin remote, when it has one.
+type VerifyResult struct {
+	Repo    models.GroupRepository
+	Dir     string
+	Problem Problem
+	Remote  string
+	Err     error
+}
+
+// Verify checks that every repository of the group is cloned and that its
+// origin remote points at the recorded repository, if one was recorded,
+// using at most limit concurrent workers.
+func (w Workspace) Verify(ctx context.Context, repos []models.GroupRepository, limit int) []VerifyResult {
+	return Parallel(ctx, repos, limit, func(ctx context.Context, repo models.GroupRepository) VerifyResult {
+		result := VerifyResult{Repo: repo, Dir: w.RepoDir(repo)}
+		if _, err := os.Stat(result.Dir); err != nil {
+			result.Problem = ProblemMissing
+			if repo.PendingClone() {
+				result.Problem = ProblemPendingClone
+			}
+			return result
+		}
+		if !git.IsRepository(ctx, result.Dir) {
+			result.Problem = ProblemNotRepository
+			return result
+		}
+		// Local repositories added without a remote are valid as they are.
+		if repo.RemoteURL == "" {
+			return result
+		}
+
+		result.Remote, result.Err = git.RemoteURL(ctx, result.Dir, "origin")
+		switch {
+		case result.Err != nil:
+		case result.Remote == "":
+			result.Problem = ProblemNoRemote
+		case RepositoryID(result.Remote) != repo.RepositoryID:
+			result.Problem = ProblemRemoteMismatch
+		}
+		return result
+	})
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/msetsma/RepoRover/core/git"
+	"github.com/msetsma/RepoRover/core/models"
+)
+
+// Workspace maps the repositories of a group onto the file system.
+type Workspace struct {
+	// Root is the clone destination shared by all groups.
+	Root  string
+	Group string
+	// Credentials are used to clone, pull and sync.
+	Credentials git.Credentials
+}
+
+// New returns the workspace of group under the clone destination root.
+func New(root, group string) Workspace {
+	return Workspace{Root: root, Group: group}
+}
+
+// Dir returns the directory repositories of the group are cloned into.
+func (w Workspace) Dir() string {
+	return filepath.Join(w.Root, w.Group)
+}
+
+// RepoDir returns the working tree of repo: its recorded path, or its
+// location under the group directory when it is pending clone.
+func (w Workspace) RepoDir(repo models.GroupRepository) string {
+	if repo.Path != "" {
+		return repo.Path
+	}
+	return filepath.Join(w.Dir(), filepath.FromSlash(repo.Name))
+}
+
+// Parallel calls fn for every item using at most limit concurrent workers
+// and returns the results in the same order as items.
+func Parallel[T, R any](ctx context.Context, items []T, limit int, fn func(context.Context, T) R) []R {
+	if limit < 1 {
+		limit = 1
+	}
+
+	results := make([]R, len(items))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = fn(ctx, item)
+		}(i, item)
+	}
+	wg.Wait()
+	return results
+}